@@ -208,6 +208,17 @@ type pkgContext struct {
 
 	// Determines if we should emit object defaults code
 	disableObjectDefaults bool
+
+	// Determines if we should emit functional-option setters and WithOptions constructors
+	// for resources
+	generateFunctionalOptions bool
+
+	// Determines if we should emit <Type>PtrFromOutput helpers that convert a <Type>Output
+	// into a <Type>PtrInput
+	generateUnionInputHelpers bool
+
+	// Module name -> build-constraint expression stamped on the module's generated files
+	moduleBuildTags map[string]string
 }
 
 func (pkg *pkgContext) detailsForType(t schema.Type) *typeDetails {
@@ -1816,6 +1827,16 @@ func (pkg *pkgContext) fieldName(r *schema.Resource, field *schema.Property) str
 
 func (pkg *pkgContext) genPlainType(w io.Writer, name, comment, deprecationMessage string,
 	properties []*schema.Property,
+) error {
+	return pkg.genPlainTypeWithAdditional(w, name, comment, deprecationMessage, properties, nil)
+}
+
+// genPlainTypeWithAdditional generates a plain Go struct for the given properties. When
+// additionalProperties is non-nil the object type is open: a catch-all AdditionalProperties
+// map field is appended whose entries are spliced into (and collected from) the object's
+// undeclared keys via the `pulumi:",inline"` tag.
+func (pkg *pkgContext) genPlainTypeWithAdditional(w io.Writer, name, comment, deprecationMessage string,
+	properties []*schema.Property, additionalProperties schema.Type,
 ) error {
 	typeRef := schema.DocRef{}
 	if _, err := pkg.printCommentWithDeprecationMessage(w, comment, deprecationMessage, typeRef, false); err != nil {
@@ -1829,6 +1850,11 @@ func (pkg *pkgContext) genPlainType(w io.Writer, name, comment, deprecationMessa
 		}
 		fmt.Fprintf(w, "\t%s %s `pulumi:\"%s\"`\n", pkg.fieldName(nil, p), pkg.typeString(codegen.ResolvedType(p.Type)), p.Name)
 	}
+	if additionalProperties != nil {
+		fmt.Fprintf(w, "\t// AdditionalProperties collects any properties of the object not declared in the schema.\n")
+		elementType := pkg.typeString(codegen.ResolvedType(additionalProperties))
+		fmt.Fprintf(w, "\tAdditionalProperties map[string]%s `pulumi:\",inline\"`\n", elementType)
+	}
 	fmt.Fprintf(w, "}\n\n")
 	return nil
 }
@@ -1970,6 +1996,15 @@ func (pkg *pkgContext) genInputTypes(
 		fmt.Fprintf(w, "\treturn (*%s)(v)\n", ptrTypeName)
 		fmt.Fprintf(w, "}\n\n")
 
+		if pkg.generateUnionInputHelpers {
+			// Together with the <Type>Ptr helper above, this lets callers supply either the
+			// plain args form or an already-constructed output as a <Type>PtrInput.
+			fmt.Fprintf(w, "// %[1]sPtrFromOutput converts a %[1]sOutput into a %[1]sPtrInput.\n", name)
+			fmt.Fprintf(w, "func %[1]sPtrFromOutput(o %[1]sOutput) %[1]sPtrInput {\n", name)
+			fmt.Fprintf(w, "\treturn o.To%sPtrOutput()\n", name)
+			fmt.Fprintf(w, "}\n\n")
+		}
+
 		pkg.genInputImplementation(w, name+"Ptr", "*"+ptrTypeName, "*"+name, false, usingGenericTypes)
 	}
 
@@ -2094,6 +2129,16 @@ func (pkg *pkgContext) genOutputTypes(w io.Writer, genArgs genOutputTypesArgs) e
 
 			fmt.Fprintf(w, "}\n\n")
 		}
+
+		if t.AdditionalProperties != nil && !genArgs.usingGenericTypes {
+			mapType := &schema.MapType{ElementType: t.AdditionalProperties}
+			outputType, applyType := pkg.outputType(mapType), pkg.typeString(mapType)
+			fmt.Fprintf(w, "// AdditionalProperties returns any properties of the object not declared in the schema.\n")
+			fmt.Fprintf(w, "func (o %sOutput) AdditionalProperties() %s {\n", name, outputType)
+			fmt.Fprintf(w, "\treturn o.ApplyT(func (v %s) %s { return v.AdditionalProperties }).(%s)\n",
+				name, applyType, outputType)
+			fmt.Fprintf(w, "}\n\n")
+		}
 	}
 
 	if details.ptrOutput && !genArgs.usingGenericTypes {
@@ -2648,6 +2693,50 @@ func (pkg *pkgContext) genResource(
 	fmt.Fprintf(w, "\treturn reflect.TypeOf((*%sArgs)(nil)).Elem()\n", cgstrings.Camel(name))
 	fmt.Fprintf(w, "}\n")
 
+	// Optionally emit functional-option setters and a constructor that builds the args
+	// struct from them. This is an alternative ergonomic surface for resources with large
+	// optional argument sets; the struct-based API above is unchanged.
+	if pkg.generateFunctionalOptions && !useGenericVariant {
+		fmt.Fprintf(w, "\n// %[1]sArgOption mutates a %[1]sArgs value; see New%[1]sWithOptions.\n", name)
+		fmt.Fprintf(w, "type %[1]sArgOption func(*%[1]sArgs)\n\n", name)
+
+		for _, p := range r.InputProperties {
+			typ := p.Type
+			if p.Plain {
+				typ = codegen.MapOptionalType(typ, func(typ schema.Type) schema.Type {
+					if input, ok := typ.(*schema.InputType); ok {
+						return input.ElementType
+					}
+					return typ
+				})
+			}
+			inputTypeName := pkg.inputType(typ)
+			if p.Plain {
+				inputTypeName = pkg.typeString(typ)
+			}
+
+			fieldName := pkg.fieldName(r, p)
+			fmt.Fprintf(w, "// %[1]sWith%[2]s sets the %[2]s argument.\n", name, fieldName)
+			fmt.Fprintf(w, "func %[1]sWith%[2]s(v %[3]s) %[1]sArgOption {\n", name, fieldName, inputTypeName)
+			fmt.Fprintf(w, "\treturn func(args *%sArgs) {\n", name)
+			fmt.Fprintf(w, "\t\targs.%s = v\n", fieldName)
+			fmt.Fprintf(w, "\t}\n")
+			fmt.Fprintf(w, "}\n\n")
+		}
+
+		fmt.Fprintf(w, "// New%[1]sWithOptions registers a new %[1]s resource, building its arguments from the\n", name)
+		fmt.Fprintf(w, "// given functional options and forwarding opts to the constructor.\n")
+		fmt.Fprintf(w, "func New%[1]sWithOptions(ctx *pulumi.Context, name string, argOpts []%[1]sArgOption,\n", name)
+		fmt.Fprintf(w, "\topts ...pulumi.ResourceOption,\n")
+		fmt.Fprintf(w, ") (*%s, error) {\n", name)
+		fmt.Fprintf(w, "\targs := &%sArgs{}\n", name)
+		fmt.Fprintf(w, "\tfor _, o := range argOpts {\n")
+		fmt.Fprintf(w, "\t\to(args)\n")
+		fmt.Fprintf(w, "\t}\n")
+		fmt.Fprintf(w, "\treturn New%s(ctx, name, args, opts...)\n", name)
+		fmt.Fprintf(w, "}\n")
+	}
+
 	// Emit resource methods.
 	for _, method := range r.Methods {
 		methodName := Title(method.Name)
@@ -3687,7 +3776,8 @@ func (pkg *pkgContext) genType(w io.Writer, obj *schema.ObjectType, usingGeneric
 
 	plainName := pkg.tokenToType(obj.Token)
 	if !usingGenericTypes {
-		if err := pkg.genPlainType(w, plainName, obj.Comment, "", obj.Properties); err != nil {
+		if err := pkg.genPlainTypeWithAdditional(w, plainName, obj.Comment, "",
+			obj.Properties, obj.AdditionalProperties); err != nil {
 			return err
 		}
 	} else {
@@ -4243,6 +4333,12 @@ func (pkg *pkgContext) genHeader(w io.Writer, goImports []string, importsAndAlia
 	fmt.Fprintf(w, "// Code generated by %v DO NOT EDIT.\n", pkg.tool)
 	fmt.Fprintf(w, "// *** WARNING: Do not edit by hand unless you're certain you know what you are doing! ***\n\n")
 
+	// If the schema gates this module behind a build constraint, stamp it on every file so
+	// the whole module (init() registrations included) can be excluded from compilation.
+	if tag := pkg.moduleBuildTags[pkg.mod]; tag != "" {
+		fmt.Fprintf(w, "//go:build %s\n\n", tag)
+	}
+
 	var pkgName string
 	if pkg.mod == "" {
 		if isUtil {
@@ -4519,6 +4615,9 @@ func generatePackageContextMap(tool string, pkg schema.PackageReference, goInfo
 				liftSingleValueMethodReturns:  goInfo.LiftSingleValueMethodReturns,
 				disableInputTypeRegistrations: goInfo.DisableInputTypeRegistrations,
 				disableObjectDefaults:         goInfo.DisableObjectDefaults,
+				generateFunctionalOptions:     goInfo.GenerateFunctionalOptions,
+				generateUnionInputHelpers:     goInfo.GenerateUnionInputHelpers,
+				moduleBuildTags:               goInfo.ModuleBuildTags,
 				internalModuleName:            internalModuleName,
 				externalPackages:              externalPkgs,
 			}
@@ -655,3 +655,157 @@ func TestRegressTypeDuplicatesInChunking(t *testing.T) {
 		assert.NotContains(t, typedefs1, typ)
 	}
 }
+
+func TestPtrOutputElemGenerated(t *testing.T) {
+	t.Parallel()
+	pkgSpec := schema.PackageSpec{
+		Name:    "test",
+		Version: "0.0.1",
+		Types: map[string]schema.ComplexTypeSpec{
+			"test:index:Settings": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Type: "object",
+					Properties: map[string]schema.PropertySpec{
+						"name": {TypeSpec: schema.TypeSpec{Type: "string"}},
+					},
+				},
+			},
+		},
+		Resources: map[string]schema.ResourceSpec{
+			"test:index:Res": {
+				InputProperties: map[string]schema.PropertySpec{
+					// Optional object input: generates SettingsPtrOutput.
+					"settings": {
+						TypeSpec: schema.TypeSpec{Ref: "#/types/test:index:Settings"},
+					},
+				},
+			},
+		},
+	}
+
+	loader := schema.NewPluginLoader(utils.NewContext(testdataPath))
+	pkg, diags, err := schema.BindSpec(pkgSpec, loader, schema.ValidationOptions{
+		AllowDanglingReferences: true,
+	})
+	require.NoError(t, err)
+	require.False(t, diags.HasErrors())
+
+	fs, err := GeneratePackage("tests", pkg, nil)
+	require.NoError(t, err)
+
+	types := string(fs["test/pulumiTypes.go"])
+	assert.Contains(t, types, "func (o SettingsPtrOutput) Elem() SettingsOutput {")
+}
+
+func TestUnionInputHelpersGenerated(t *testing.T) {
+	t.Parallel()
+	pkgSpec := schema.PackageSpec{
+		Name:    "test",
+		Version: "0.0.1",
+		Types: map[string]schema.ComplexTypeSpec{
+			"test:index:Settings": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Type: "object",
+					Properties: map[string]schema.PropertySpec{
+						"name": {TypeSpec: schema.TypeSpec{Type: "string"}},
+					},
+				},
+			},
+		},
+		Resources: map[string]schema.ResourceSpec{
+			"test:index:Res": {
+				InputProperties: map[string]schema.PropertySpec{
+					// Optional object input: generates SettingsPtrInput.
+					"settings": {
+						TypeSpec: schema.TypeSpec{Ref: "#/types/test:index:Settings"},
+					},
+				},
+			},
+		},
+	}
+
+	loader := schema.NewPluginLoader(utils.NewContext(testdataPath))
+
+	bind := func(t *testing.T) *schema.Package {
+		pkg, diags, err := schema.BindSpec(pkgSpec, loader, schema.ValidationOptions{
+			AllowDanglingReferences: true,
+		})
+		require.NoError(t, err)
+		require.False(t, diags.HasErrors())
+		return pkg
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+		pkg := bind(t)
+		pkg.Language = map[string]any{"go": GoPackageInfo{GenerateUnionInputHelpers: true}}
+
+		fs, err := GeneratePackage("tests", pkg, nil)
+		require.NoError(t, err)
+
+		types := string(fs["test/pulumiTypes.go"])
+		assert.Contains(t, types, "func SettingsPtr(v *SettingsArgs) SettingsPtrInput {")
+		assert.Contains(t, types, "func SettingsPtrFromOutput(o SettingsOutput) SettingsPtrInput {")
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Parallel()
+		pkg := bind(t)
+
+		fs, err := GeneratePackage("tests", pkg, nil)
+		require.NoError(t, err)
+
+		types := string(fs["test/pulumiTypes.go"])
+		assert.NotContains(t, types, "SettingsPtrFromOutput")
+	})
+}
+
+func TestOpenObjectTypeGeneratesAdditionalProperties(t *testing.T) {
+	t.Parallel()
+
+	pkgSpec := schema.PackageSpec{
+		Name:    "test",
+		Version: "0.0.1",
+		Types: map[string]schema.ComplexTypeSpec{
+			"test:index:Open": {
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Type: "object",
+					Properties: map[string]schema.PropertySpec{
+						"name": {TypeSpec: schema.TypeSpec{Type: "string"}},
+					},
+					AdditionalProperties: &schema.TypeSpec{Ref: "pulumi.json#/Any"},
+				},
+			},
+		},
+		Resources: map[string]schema.ResourceSpec{
+			"test:index:Res": {
+				InputProperties: map[string]schema.PropertySpec{
+					"open": {TypeSpec: schema.TypeSpec{Ref: "#/types/test:index:Open"}},
+				},
+				ObjectTypeSpec: schema.ObjectTypeSpec{
+					Properties: map[string]schema.PropertySpec{
+						"open": {TypeSpec: schema.TypeSpec{Ref: "#/types/test:index:Open"}},
+					},
+				},
+			},
+		},
+	}
+
+	loader := schema.NewPluginLoader(utils.NewContext(testdataPath))
+	pkg, diags, err := schema.BindSpec(pkgSpec, loader, schema.ValidationOptions{
+		AllowDanglingReferences: true,
+	})
+	require.NoError(t, err)
+	require.False(t, diags.HasErrors())
+
+	fs, err := GeneratePackage("tests", pkg, nil)
+	require.NoError(t, err)
+
+	code := string(fs["test/pulumiTypes.go"])
+
+	// The plain struct gains a catch-all map field wired to the inline unmarshal path.
+	assert.Contains(t, code, "AdditionalProperties map[string]interface{} `pulumi:\",inline\"`")
+
+	// The output type exposes the dynamic properties as a map output.
+	assert.Contains(t, code, "func (o OpenOutput) AdditionalProperties() pulumi.MapOutput {")
+}
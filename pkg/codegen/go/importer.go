@@ -83,6 +83,28 @@ type GoPackageInfo struct {
 	// space saving measure.
 	DisableObjectDefaults bool `json:"disableObjectDefaults,omitempty"`
 
+	// ModuleBuildTags maps a module name to a build-constraint expression that is stamped
+	// (as a `//go:build` line) on every file generated for that module, including the file
+	// carrying the module's init() registrations. For very large SDKs this lets users
+	// exclude modules they don't import from compilation entirely. Expressions of the form
+	// "!<pkg>_minimal" keep the default build unchanged while letting minimal builds opt
+	// out with `-tags <pkg>_minimal`.
+	//
+	//    { "wafregional": "!aws_minimal" }
+	//
+	ModuleBuildTags map[string]string `json:"moduleBuildTags,omitempty"`
+
+	// GenerateFunctionalOptions determines whether the code generator emits functional-option
+	// setters and a New<Resource>WithOptions constructor for each resource, as an alternative
+	// to populating the args struct directly. The struct-based API is always generated.
+	GenerateFunctionalOptions bool `json:"generateFunctionalOptions,omitempty"`
+
+	// GenerateUnionInputHelpers determines whether the code generator emits, for each object
+	// type with a pointer input, a <Type>PtrFromOutput helper that converts a <Type>Output
+	// into a <Type>PtrInput. Together with the always-generated <Type>Ptr helper this lets
+	// callers supply either the plain args struct or an output without manual conversions.
+	GenerateUnionInputHelpers bool `json:"generateUnionInputHelpers,omitempty"`
+
 	// GenerateExtraInputTypes determines whether or not the code generator generates input (and output) types for
 	// all plain types, instead of for only types that are used as input/output types.
 	GenerateExtraInputTypes bool `json:"generateExtraInputTypes,omitempty"`
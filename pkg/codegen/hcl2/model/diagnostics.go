@@ -52,11 +52,35 @@ func ExprNotConvertible(destType Type, expr Expression) *hcl.Diagnostic {
 		expr.Type().Pretty(), destType.Pretty())
 }
 
+// annotateConversionPath prefixes the summaries of lazily-produced conversion diagnostics
+// with a path element (a union member index or an object field name) so that nested
+// conversion failures read like `union member 1: field "foo": cannot assign ...`.
+func annotateConversionPath(element string, why lazyDiagnostics) lazyDiagnostics {
+	if why == nil {
+		return nil
+	}
+	return func() hcl.Diagnostics {
+		diags := why()
+		annotated := make(hcl.Diagnostics, len(diags))
+		for i, d := range diags {
+			nd := *d
+			nd.Summary = fmt.Sprintf("%s: %s", element, d.Summary)
+			annotated[i] = &nd
+		}
+		return annotated
+	}
+}
+
 func typeNotConvertible(dest, src Type) *hcl.Diagnostic {
 	return &hcl.Diagnostic{Severity: hcl.DiagError, Summary: fmt.Sprintf("cannot assign value of type %s to type %s",
 		src.Pretty(), dest.Pretty())}
 }
 
+func valueMayBeNull(dest Type) *hcl.Diagnostic {
+	return &hcl.Diagnostic{Severity: hcl.DiagError, Summary: fmt.Sprintf("value may be null but %s is not optional",
+		dest.Pretty())}
+}
+
 func tuplesHaveDifferentLengths(dest, src *TupleType) *hcl.Diagnostic {
 	return &hcl.Diagnostic{Severity: hcl.DiagError, Summary: fmt.Sprintf("tuples %v and %v have different lengths",
 		dest, src)}
@@ -1405,6 +1405,14 @@ type LiteralValueExpression struct {
 	exprType Type
 }
 
+// NewTypedLiteralValueExpression returns a literal value expression whose static type is typ
+// rather than the type inferred from value. Code generators use this to tag literals with
+// language-specific types (e.g. bytes) that have no PCL-level representation. Typechecking the
+// returned expression recomputes its type from the value, discarding typ.
+func NewTypedLiteralValueExpression(value cty.Value, typ Type) *LiteralValueExpression {
+	return &LiteralValueExpression{Value: value, exprType: typ}
+}
+
 func literalValueType(value cty.Value) Type {
 	if value.IsNull() {
 		return NoneType
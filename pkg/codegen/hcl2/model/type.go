@@ -46,6 +46,34 @@ func (k ConversionKind) GoString() string {
 	}
 }
 
+// BestConversion returns the most permissive of the given conversion kinds, following the
+// ordering NoConversion < UnsafeConversion < SafeConversion. If no kinds are given, the
+// result is NoConversion. This is the aggregation used when any one of a set of conversions
+// suffices (e.g. conversion to a union type).
+func BestConversion(kinds ...ConversionKind) ConversionKind {
+	best := NoConversion
+	for _, k := range kinds {
+		if k > best {
+			best = k
+		}
+	}
+	return best
+}
+
+// WorstConversion returns the least permissive of the given conversion kinds, following the
+// ordering NoConversion < UnsafeConversion < SafeConversion. If no kinds are given, the
+// result is SafeConversion. This is the aggregation used when all of a set of conversions
+// must succeed (e.g. conversion from a union type).
+func WorstConversion(kinds ...ConversionKind) ConversionKind {
+	worst := SafeConversion
+	for _, k := range kinds {
+		if k < worst {
+			worst = k
+		}
+	}
+	return worst
+}
+
 func (k ConversionKind) Exists() bool {
 	switch k {
 	case UnsafeConversion, SafeConversion:
@@ -121,9 +149,22 @@ func assignableFrom(dest, src Type, assignableFromImpl func() bool) bool {
 	return assignableFromImpl()
 }
 
+// cacheEntry memoizes the result of a conversionFrom query for a particular source type.
+// Results are recorded separately for non-unifying (slot 0) and unifying (slot 1) queries:
+// a unifying conversion can be more permissive than a non-unifying one, so the two must
+// never be answered from each other's slot.
 type cacheEntry struct {
-	kind  ConversionKind
-	diags lazyDiagnostics
+	kind  [2]ConversionKind
+	diags [2]lazyDiagnostics
+	valid [2]bool
+}
+
+// cacheSlot returns the cacheEntry slot for the given unifying flag.
+func cacheSlot(unifying bool) int {
+	if unifying {
+		return 1
+	}
+	return 0
 }
 
 // cycleSet tracks `(destination, source)` pairs currently mid-flight in a
@@ -154,38 +195,42 @@ func conversionFrom(dest, src Type, unifying bool, seen cycleSet,
 		return SafeConversion, nil
 	}
 
-	if c, ok := cache.Load(src); ok {
-		return c.kind, c.diags
+	slot := cacheSlot(unifying)
+	if c, ok := cache.Load(src); ok && c.valid[slot] {
+		return c.kind[slot], c.diags[slot]
+	}
+
+	// store records the result for this query's unifying flag, preserving any result
+	// already cached for the other flag.
+	store := func(src Type, kind ConversionKind, diags lazyDiagnostics) {
+		if cache == nil {
+			return
+		}
+		entry, _ := cache.Load(src)
+		entry.kind[slot], entry.diags[slot], entry.valid[slot] = kind, diags, true
+		cache.Store(src, entry)
 	}
 
 	switch src := src.(type) {
 	case *UnionType:
 		kind, diags := src.conversionTo(dest, unifying, seen)
-		if cache != nil {
-			cache.Store(src, cacheEntry{kind: kind, diags: diags})
-		}
+		store(src, kind, diags)
 		return kind, diags
 	case *ConstType:
 		// We want `EnumType`s too see const types, since they allow safe
 		// conversions.
 		if _, ok := dest.(*EnumType); !ok {
 			kind, diags := conversionFrom(dest, src.Type, unifying, seen, cache, conversionFromImpl)
-			if cache != nil {
-				cache.Store(src, cacheEntry{kind, diags})
-			}
+			store(src, kind, diags)
 			return kind, diags
 		}
 	}
 	if src == DynamicType {
-		if cache != nil {
-			cache.Store(src, cacheEntry{UnsafeConversion, nil})
-		}
+		store(src, UnsafeConversion, nil)
 		return UnsafeConversion, nil
 	}
 	kind, diags := conversionFromImpl()
-	if cache != nil {
-		cache.Store(src, cacheEntry{kind, diags})
-	}
+	store(src, kind, diags)
 
 	contract.Assertf(
 		kind.Exists() || diags != nil,
@@ -217,6 +262,19 @@ func unify(t0, t1 Type, unify func() (Type, ConversionKind)) (Type, ConversionKi
 		case conversionFrom > conversionTo:
 			return t0, conversionFrom
 		}
+		// Unifying two optional types preserves optionality: unify the element types and
+		// re-wrap the result, using the element unification's conversion kind rather than
+		// the kind implied by unioning all of the members together.
+		if u0, ok := t0.(*UnionType); ok {
+			if u1, ok := t1.(*UnionType); ok {
+				if e0, ok := u0.optionalElementType(); ok {
+					if e1, ok := u1.optionalElementType(); ok {
+						element, conversionKind := e0.unify(e1)
+						return NewOptionalType(element), conversionKind
+					}
+				}
+			}
+		}
 		if conversionFrom == NoConversion {
 			return NewUnionType(t0, t1), SafeConversion
 		}
@@ -303,7 +303,7 @@ func (t *ObjectType) conversionFrom(src Type, unifying bool, seen cycleSet) (Con
 					src = NoneType
 				}
 				if ck, why := dst.conversionFrom(src, unifying, seen); ck < conversionKind {
-					conversionKind, diags = ck, why
+					conversionKind, diags = ck, annotateConversionPath(fmt.Sprintf("field %q", k), why)
 					if conversionKind == NoConversion {
 						break
 					}
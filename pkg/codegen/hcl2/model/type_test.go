@@ -1241,3 +1241,38 @@ func (*y) f() string {
 var _ i = (*y)(nil)
 
 type z struct{}
+
+func TestBestAndWorstConversion(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, NoConversion, BestConversion())
+	assert.Equal(t, SafeConversion, WorstConversion())
+
+	assert.Equal(t, SafeConversion, BestConversion(NoConversion, SafeConversion, UnsafeConversion))
+	assert.Equal(t, UnsafeConversion, BestConversion(NoConversion, UnsafeConversion))
+	assert.Equal(t, NoConversion, BestConversion(NoConversion, NoConversion))
+
+	assert.Equal(t, NoConversion, WorstConversion(NoConversion, SafeConversion, UnsafeConversion))
+	assert.Equal(t, UnsafeConversion, WorstConversion(SafeConversion, UnsafeConversion))
+	assert.Equal(t, SafeConversion, WorstConversion(SafeConversion, SafeConversion))
+}
+
+func TestConversionCacheKeyedOnUnifying(t *testing.T) {
+	t.Parallel()
+
+	dest := NewObjectType(map[string]Type{"a": StringType})
+	src := NewObjectType(map[string]Type{})
+
+	// Non-unifying: property "a" has no source, so there is no conversion.
+	kind, _ := dest.conversionFrom(src, false, nil)
+	assert.Equal(t, NoConversion, kind)
+
+	// The unifying query for the same (dest, src) pair is more permissive and must not be
+	// answered from the non-unifying cache entry.
+	kind, _ = dest.conversionFrom(src, true, nil)
+	assert.Equal(t, SafeConversion, kind)
+
+	// Nor may the unifying result poison subsequent non-unifying queries.
+	kind, _ = dest.conversionFrom(src, false, nil)
+	assert.Equal(t, NoConversion, kind)
+}
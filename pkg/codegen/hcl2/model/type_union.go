@@ -16,6 +16,7 @@ package model
 
 import (
 	"fmt"
+	"reflect"
 	"slices"
 	"sort"
 	"strings"
@@ -36,11 +37,23 @@ type UnionType struct {
 	// Annotations records any annotations associated with the object type.
 	Annotations []any
 
+	// memberAnnotations records annotations attached to individual union members,
+	// parallel to ElementTypes. Use MemberAnnotations to read them.
+	memberAnnotations [][]any
+
 	s atomic.Value // Value<string>
 
 	cache *gsync.Map[Type, cacheEntry]
 }
 
+// DeprecationAnnotation marks a union member as deprecated. Code generators can attach
+// it to a member via NewUnionTypeAnnotatedMembers and use it to emit `Deprecated:` doc
+// comments on the generated member constructors.
+type DeprecationAnnotation struct {
+	// Message is the deprecation message, if any.
+	Message string
+}
+
 // NewUnionTypeAnnotated creates a new union type with the given element types and annotations.
 // NewUnionTypeAnnotated enforces 3 properties on the returned type:
 // 1. Any element types that are union types are replaced with their element types.
@@ -48,41 +61,86 @@ type UnionType struct {
 // 3. Unions have have more then 1 type. If only a single type is left after (1) and (2),
 // it is returned as is.
 func NewUnionTypeAnnotated(types []Type, annotations ...any) Type {
-	var elementTypes []Type
-	for _, t := range types {
+	return NewUnionTypeAnnotatedMembers(types, nil, annotations...)
+}
+
+// NewUnionTypeAnnotatedMembers creates a new union type where memberAnnotations[i] carries
+// annotations (e.g. DeprecationAnnotation) attached to types[i] rather than to the union as
+// a whole. Member annotations follow their element through flattening, sorting, and
+// deduplication and can be read back with MemberAnnotations. The trailing annotations apply
+// to the whole union, as in NewUnionTypeAnnotated.
+func NewUnionTypeAnnotatedMembers(types []Type, memberAnnotations [][]any, annotations ...any) Type {
+	type member struct {
+		t           Type
+		annotations []any
+	}
+
+	var members []member
+	for i, t := range types {
+		var anns []any
+		if i < len(memberAnnotations) {
+			anns = memberAnnotations[i]
+		}
 		if union, isUnion := t.(*UnionType); isUnion {
-			elementTypes = append(elementTypes, union.ElementTypes...)
+			for j, e := range union.ElementTypes {
+				memberAnns := union.MemberAnnotations(j)
+				merged := anns
+				if len(memberAnns) != 0 {
+					merged = make([]any, 0, len(anns)+len(memberAnns))
+					merged = append(merged, anns...)
+					merged = append(merged, memberAnns...)
+				}
+				members = append(members, member{t: e, annotations: merged})
+			}
 		} else {
-			elementTypes = append(elementTypes, t)
+			members = append(members, member{t: t, annotations: anns})
 		}
 	}
 
 	// Remove duplicate types
 	// We first sort the types so duplicates will be adjacent
-	sort.Slice(elementTypes, func(i, j int) bool {
-		return elementTypes[i].String() < elementTypes[j].String()
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].t.String() < members[j].t.String()
 	})
-	// We then filter out adjacent duplicates
+	// We then filter out adjacent duplicates, merging the annotations of each duplicate
+	// into those of its first occurrence.
 	dst := 0
-	for src := 0; src < len(elementTypes); {
-		for src < len(elementTypes) && elementTypes[src].Equals(elementTypes[dst]) {
-			src++
-		}
-		dst++
-
-		if src < len(elementTypes) {
-			elementTypes[dst] = elementTypes[src]
+	for src := 1; src < len(members); src++ {
+		if members[src].t.Equals(members[dst].t) {
+			members[dst].annotations = append(members[dst].annotations, members[src].annotations...)
+		} else {
+			dst++
+			members[dst] = members[src]
 		}
 	}
-	elementTypes = elementTypes[:dst]
+	if len(members) != 0 {
+		members = members[:dst+1]
+	}
 
 	// If the union turns out to be the union of a single type, just return the underlying
 	// type.
-	if len(elementTypes) == 1 {
-		return elementTypes[0]
+	if len(members) == 1 {
+		return members[0].t
 	}
 
-	return &UnionType{ElementTypes: elementTypes, Annotations: annotations, cache: &gsync.Map[Type, cacheEntry]{}}
+	elementTypes := make([]Type, len(members))
+	var elementAnnotations [][]any
+	for i, m := range members {
+		elementTypes[i] = m.t
+		if len(m.annotations) != 0 {
+			if elementAnnotations == nil {
+				elementAnnotations = make([][]any, len(members))
+			}
+			elementAnnotations[i] = m.annotations
+		}
+	}
+
+	return &UnionType{
+		ElementTypes:      elementTypes,
+		Annotations:       annotations,
+		memberAnnotations: elementAnnotations,
+		cache:             &gsync.Map[Type, cacheEntry]{},
+	}
 }
 
 // NewUnionType creates a new union type with the given element types. Any element types that are union types are
@@ -97,6 +155,125 @@ func NewUnionType(types ...Type) Type {
 	return NewUnionTypeAnnotated(types, annotations...)
 }
 
+// MergeUnions merges two unions into one, unioning their element types and concatenating
+// their whole-union annotations with duplicates removed. Per-member annotations follow their
+// elements through flattening as in NewUnionTypeAnnotatedMembers. If the merged union is
+// left with a single member, that member is returned as is.
+func MergeUnions(a, b *UnionType) Type {
+	annotations := slices.Clone(a.Annotations)
+	for _, ann := range b.Annotations {
+		duplicate := slices.ContainsFunc(annotations, func(existing any) bool {
+			return reflect.DeepEqual(existing, ann)
+		})
+		if !duplicate {
+			annotations = append(annotations, ann)
+		}
+	}
+	return NewUnionTypeAnnotated([]Type{a, b}, annotations...)
+}
+
+// UnionFromGoTypes builds the union of the model types corresponding to the given Go
+// types, bridging the reflection world with the HCL2 model for programmatic schema
+// construction. Strings, booleans, integers, and floats map to the corresponding primitive
+// types; slices and arrays map to list types, string-keyed maps to map types, pointers to
+// optionals of their element type, and the empty interface to DynamicType. Unmappable
+// types produce an error. As with NewUnionType, a union of a single type is returned as
+// that type.
+func UnionFromGoTypes(types ...reflect.Type) (Type, error) {
+	if len(types) == 0 {
+		return nil, fmt.Errorf("at least one type is required")
+	}
+	elements := make([]Type, len(types))
+	for i, t := range types {
+		element, err := typeFromGoType(t)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return NewUnionType(elements...), nil
+}
+
+// typeFromGoType maps a single Go type to its model type.
+func typeFromGoType(t reflect.Type) (Type, error) {
+	//nolint:exhaustive // Remaining kinds fall through to the unmappable-type error.
+	switch t.Kind() {
+	case reflect.Bool:
+		return BoolType, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return IntType, nil
+	case reflect.Float32, reflect.Float64:
+		return NumberType, nil
+	case reflect.String:
+		return StringType, nil
+	case reflect.Slice, reflect.Array:
+		element, err := typeFromGoType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewListType(element), nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot map Go type %v to a model type: map keys must be strings", t)
+		}
+		element, err := typeFromGoType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewMapType(element), nil
+	case reflect.Pointer:
+		element, err := typeFromGoType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return NewOptionalType(element), nil
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return DynamicType, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot map Go type %v to a model type", t)
+}
+
+// MemberAnnotations returns the annotations attached to the i'th element of the union, if
+// any.
+func (t *UnionType) MemberAnnotations(i int) []any {
+	if t.memberAnnotations == nil || i < 0 || i >= len(t.memberAnnotations) {
+		return nil
+	}
+	return t.memberAnnotations[i]
+}
+
+// Filter returns a union of only the members for which keep returns true. The result is
+// built with NewUnionTypeAnnotatedMembers, so flattening, deduplication, and both
+// whole-union and per-member annotations are preserved, and a single surviving member is
+// returned as itself. If no members survive, Filter returns NoneType, the type inhabited
+// only by the absence of a value.
+func (t *UnionType) Filter(keep func(Type) bool) Type {
+	types := make([]Type, 0, len(t.ElementTypes))
+	var memberAnnotations [][]any
+	for i, e := range t.ElementTypes {
+		if !keep(e) {
+			continue
+		}
+		types = append(types, e)
+		if anns := t.MemberAnnotations(i); len(anns) != 0 {
+			for len(memberAnnotations) < len(types)-1 {
+				memberAnnotations = append(memberAnnotations, nil)
+			}
+			memberAnnotations = append(memberAnnotations, anns)
+		}
+	}
+	switch len(types) {
+	case 0:
+		return NoneType
+	case len(t.ElementTypes):
+		return t
+	}
+	return NewUnionTypeAnnotatedMembers(types, memberAnnotations, t.Annotations...)
+}
+
 // NewOptionalType returns a new union(T, None).
 func NewOptionalType(t Type) Type {
 	return NewUnionType(t, NoneType)
@@ -107,6 +284,28 @@ func IsOptionalType(t Type) bool {
 	return t != DynamicType && t.AssignableFrom(NoneType)
 }
 
+// optionalElementType returns the type of t's non-None members if t is an optional type, i.e.
+// the T in union(T, None). If t has multiple non-None members, they are returned as a union.
+// The second return value is false if t does not contain None or contains nothing else.
+func (t *UnionType) optionalElementType() (Type, bool) {
+	hasNone := false
+	elements := slice.Prealloc[Type](len(t.ElementTypes))
+	for _, e := range t.ElementTypes {
+		if e == NoneType {
+			hasNone = true
+		} else {
+			elements = append(elements, e)
+		}
+	}
+	if !hasNone || len(elements) == 0 {
+		return nil, false
+	}
+	if len(elements) == 1 {
+		return elements[0], true
+	}
+	return NewUnionType(elements...), true
+}
+
 // SyntaxNode returns the syntax node for the type. This is always syntax.None.
 func (*UnionType) SyntaxNode() hclsyntax.Node {
 	return syntax.None
@@ -201,6 +400,13 @@ func (t *UnionType) equals(other Type, seen map[Type]struct{}) bool {
 	if !ok {
 		return false
 	}
+	// Fast path: NewUnionTypeAnnotated produces canonical (sorted, deduplicated) element
+	// sets, so two unions whose cached strings match are equal. Annotated unions embed
+	// their own pointer in the string, so annotation differences cannot produce false
+	// positives. Mismatched strings still fall back to the element-wise comparison.
+	if ts, os := t.s.Load(), otherUnion.s.Load(); ts != nil && os != nil && ts.(string) == os.(string) {
+		return true
+	}
 	if len(t.ElementTypes) != len(otherUnion.ElementTypes) {
 		return false
 	}
@@ -226,6 +432,25 @@ func (t *UnionType) AssignableFrom(src Type) bool {
 	})
 }
 
+// IsSubsetOf returns true if every member of this union is assignable to some member of
+// other, i.e. t is a subtype of other. This is useful for validating that a narrowed union
+// still fits a broader declared type during schema evolution.
+func (t *UnionType) IsSubsetOf(other *UnionType) bool {
+	for _, e := range t.ElementTypes {
+		assignable := false
+		for _, o := range other.ElementTypes {
+			if o.AssignableFrom(e) {
+				assignable = true
+				break
+			}
+		}
+		if !assignable {
+			return false
+		}
+	}
+	return true
+}
+
 // ConversionFrom returns the kind of conversion (if any) that is possible from the source type to this type. A union
 // type is convertible from a source type if any of its elements are convertible from the source type. If any element
 // type is safely convertible, the conversion is safe; if no element is safely convertible but some element is unsafely
@@ -235,6 +460,19 @@ func (t *UnionType) ConversionFrom(src Type) ConversionKind {
 	return kind
 }
 
+// SafelyConvertibleFrom returns true only when at least one member of the union is safely
+// convertible from the source type, short-circuiting on the first safe member. Codegen
+// passes that want only safe conversions can use this predicate instead of comparing
+// ConversionFrom against SafeConversion, treating unsafe conversions as failures.
+func (t *UnionType) SafelyConvertibleFrom(src Type) bool {
+	for _, e := range t.ElementTypes {
+		if kind, _ := e.conversionFrom(src, false, nil); kind == SafeConversion {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *UnionType) conversionFrom(src Type, unifying bool, seen cycleSet) (ConversionKind, lazyDiagnostics) {
 	return conversionFrom(t, src, unifying, seen, t.cache, func() (ConversionKind, lazyDiagnostics) {
 		var conversionKind ConversionKind
@@ -246,20 +484,32 @@ func (t *UnionType) conversionFrom(src Type, unifying bool, seen cycleSet) (Conv
 			return SafeConversion, nil
 		}
 
-		for _, t := range t.ElementTypes {
+		for i, t := range t.ElementTypes {
 			ck, why := t.conversionFrom(src, unifying, seen)
 			if ck > conversionKind {
 				conversionKind = ck
 			} else if why != nil {
-				diags = append(diags, why)
+				// Record which member rejected the value so union-heavy failures point at
+				// the specific member (and, via nested annotations, the failing field).
+				diags = append(diags, annotateConversionPath(fmt.Sprintf("union member %d", i), why))
 			}
 		}
 		if conversionKind == NoConversion {
+			// If the only problem is nullability -- the source is null but the union has no None
+			// member -- report that directly instead of a generic per-member assignability failure.
+			srcType := src
+			if cns, ok := srcType.(*ConstType); ok {
+				srcType = cns.Type
+			}
+			if srcType == NoneType && !t.AssignableFrom(NoneType) {
+				return NoConversion, func() hcl.Diagnostics {
+					return hcl.Diagnostics{valueMayBeNull(t)}
+				}
+			}
 			return NoConversion, func() hcl.Diagnostics {
 				var all hcl.Diagnostics
 				for _, why := range diags {
-					//nolint:errcheck
-					all.Extend(why())
+					all = all.Extend(why())
 				}
 				return all
 			}
@@ -304,7 +554,7 @@ func (t *UnionType) string(seen map[Type]struct{}) string {
 	}
 
 	annotations := ""
-	if len(t.Annotations) != 0 {
+	if len(t.Annotations) != 0 || t.memberAnnotations != nil {
 		annotations = fmt.Sprintf(", annotated(%p)", t)
 	}
 
@@ -313,6 +563,119 @@ func (t *UnionType) string(seen map[Type]struct{}) string {
 	return s
 }
 
+// CanonicalString returns a deterministic rendering of the union suitable for golden-file
+// comparison. Members are rendered in canonical order -- alphabetically by their rendered
+// form, with the None member last -- and annotations are rendered by content (their %v
+// formatting) rather than by pointer identity as String does, so two structurally-equal
+// unions always produce identical canonical strings.
+func (t *UnionType) CanonicalString() string {
+	c := t.Canonicalize()
+
+	var noneElement string
+	hasNone := false
+	elements := slice.Prealloc[string](len(c.ElementTypes))
+	for i, e := range c.ElementTypes {
+		s := e.String()
+		if anns := c.MemberAnnotations(i); len(anns) != 0 {
+			s += annotationString(anns)
+		}
+		if e == NoneType {
+			noneElement, hasNone = s, true
+			continue
+		}
+		elements = append(elements, s)
+	}
+	sort.Strings(elements)
+	if hasNone {
+		elements = append(elements, noneElement)
+	}
+
+	s := fmt.Sprintf("union(%s)", strings.Join(elements, ", "))
+	if len(c.Annotations) != 0 {
+		s += annotationString(c.Annotations)
+	}
+	return s
+}
+
+// annotationString renders a set of annotations by content using their %v formatting.
+func annotationString(anns []any) string {
+	parts := make([]string, len(anns))
+	for i, a := range anns {
+		parts[i] = fmt.Sprintf("%v", a)
+	}
+	return fmt.Sprintf(" annotated(%s)", strings.Join(parts, ", "))
+}
+
+// Canonicalize returns a union equivalent to t whose NoneType member, if any, is ordered
+// last. NewUnionType sorts members alphabetically, which can place NoneType anywhere;
+// generators that iterate ElementTypes directly often want to render the non-None members
+// first and the null member last. Member annotations follow their members. If t has no
+// NoneType member or it is already last, t is returned unchanged. Note that the reordered
+// union compares unequal to t, so the result is intended for rendering and iteration
+// rather than for type checking.
+func (t *UnionType) Canonicalize() *UnionType {
+	noneIdx := -1
+	for i, e := range t.ElementTypes {
+		if e == NoneType {
+			noneIdx = i
+			break
+		}
+	}
+	if noneIdx < 0 || noneIdx == len(t.ElementTypes)-1 {
+		return t
+	}
+
+	order := make([]int, 0, len(t.ElementTypes))
+	for i := range t.ElementTypes {
+		if i != noneIdx {
+			order = append(order, i)
+		}
+	}
+	order = append(order, noneIdx)
+
+	elementTypes := make([]Type, len(order))
+	var memberAnnotations [][]any
+	for dst, src := range order {
+		elementTypes[dst] = t.ElementTypes[src]
+		if anns := t.MemberAnnotations(src); len(anns) != 0 {
+			if memberAnnotations == nil {
+				memberAnnotations = make([][]any, len(order))
+			}
+			memberAnnotations[dst] = anns
+		}
+	}
+
+	return &UnionType{
+		ElementTypes:      elementTypes,
+		Annotations:       t.Annotations,
+		memberAnnotations: memberAnnotations,
+		cache:             &gsync.Map[Type, cacheEntry]{},
+	}
+}
+
+// ConvertibleMembers returns the members of the union to which src converts safely. This
+// lets a generator enumerate the targets a value could satisfy, e.g. to emit an overload
+// per member.
+func (t *UnionType) ConvertibleMembers(src Type) []Type {
+	return t.membersConvertibleFrom(src, SafeConversion)
+}
+
+// UnsafeConvertibleMembers returns the members of the union to which src converts, but
+// only unsafely.
+func (t *UnionType) UnsafeConvertibleMembers(src Type) []Type {
+	return t.membersConvertibleFrom(src, UnsafeConversion)
+}
+
+func (t *UnionType) membersConvertibleFrom(src Type, kind ConversionKind) []Type {
+	var members []Type
+	for _, e := range t.ElementTypes {
+		if k, _ := e.conversionFrom(src, false, nil); k == kind {
+			members = append(members, e)
+		}
+	}
+	return members
+}
+
 func (t *UnionType) unify(other Type) (Type, ConversionKind) {
 	return unify(t, other, func() (Type, ConversionKind) {
 		return t.unifyTo(other)
@@ -15,9 +15,14 @@
 package model
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestPrettyPrintingUnionType(t *testing.T) {
@@ -55,3 +60,362 @@ func TestPrettyPrintingSelfReferencingUnionType(t *testing.T) {
 	pretty := union.Pretty().String()
 	assert.Equal(t, "string | list({ selfReferences: string | int })", pretty)
 }
+
+func TestUnionEqualsCachedStringFastPath(t *testing.T) {
+	t.Parallel()
+
+	a := NewUnionType(StringType, IntType, BoolType)
+	b := NewUnionType(BoolType, IntType, StringType)
+
+	// Populate the cached strings and confirm the fast path agrees with the element-wise
+	// comparison in both directions.
+	_, _ = a.String(), b.String()
+	assert.True(t, a.Equals(b))
+	assert.True(t, b.Equals(a))
+
+	c := NewUnionType(StringType, NumberType)
+	_ = c.String()
+	assert.False(t, a.Equals(c))
+}
+
+func BenchmarkUnionEquals(b *testing.B) {
+	elements := make([]Type, 0, 100)
+	for i := 0; i < 100; i++ {
+		elements = append(elements, NewConstType(StringType, cty.StringVal(fmt.Sprintf("member%d", i))))
+	}
+	x := NewUnionType(elements...)
+	y := NewUnionType(elements...)
+
+	// Populate the cached strings so the benchmark measures the fast path.
+	_, _ = x.String(), y.String()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !x.Equals(y) {
+			b.Fatal("expected unions to be equal")
+		}
+	}
+}
+
+func TestUnionMemberAnnotations(t *testing.T) {
+	t.Parallel()
+
+	dep := DeprecationAnnotation{Message: "use NumberType instead"}
+	union := NewUnionTypeAnnotatedMembers(
+		[]Type{StringType, IntType},
+		[][]any{nil, {dep}},
+	).(*UnionType)
+
+	// Member annotations stay attached to their element after canonical sorting.
+	for i, e := range union.ElementTypes {
+		if e.Equals(IntType) {
+			assert.Equal(t, []any{dep}, union.MemberAnnotations(i))
+		} else {
+			assert.Nil(t, union.MemberAnnotations(i))
+		}
+	}
+
+	// Flattening a nested union preserves the annotations of its members.
+	flattened := NewUnionType(union, BoolType).(*UnionType)
+	found := false
+	for i, e := range flattened.ElementTypes {
+		if e.Equals(IntType) {
+			assert.Equal(t, []any{dep}, flattened.MemberAnnotations(i))
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	// Deduplication merges the annotations of duplicate members.
+	other := DeprecationAnnotation{Message: "other"}
+	merged := NewUnionTypeAnnotatedMembers(
+		[]Type{IntType, IntType, StringType},
+		[][]any{{dep}, {other}, nil},
+	).(*UnionType)
+	for i, e := range merged.ElementTypes {
+		if e.Equals(IntType) {
+			assert.Equal(t, []any{dep, other}, merged.MemberAnnotations(i))
+		}
+	}
+
+	// Out-of-range indices are tolerated.
+	assert.Nil(t, merged.MemberAnnotations(-1))
+	assert.Nil(t, merged.MemberAnnotations(100))
+}
+
+func TestUnionConversionDiagnosticsIncludePath(t *testing.T) {
+	t.Parallel()
+
+	union := NewUnionType(
+		NewObjectType(map[string]Type{"foo": NewListType(StringType)}),
+		NewObjectType(map[string]Type{"bar": BoolType}),
+	).(*UnionType)
+	src := NewObjectType(map[string]Type{"foo": NumberType})
+
+	kind, why := union.conversionFrom(src, false, nil)
+	assert.Equal(t, NoConversion, kind)
+	require.NotNil(t, why)
+
+	var summaries []string
+	for _, d := range why() {
+		summaries = append(summaries, d.Summary)
+	}
+	joined := strings.Join(summaries, "\n")
+	assert.Contains(t, joined, "union member ")
+	assert.Contains(t, joined, `field "foo"`)
+}
+
+func TestUnionFilter(t *testing.T) {
+	t.Parallel()
+
+	union := NewUnionType(StringType, IntType, BoolType, NoneType).(*UnionType)
+
+	// Dropping a member yields a smaller union.
+	noNone := union.Filter(func(e Type) bool { return e != NoneType })
+	filtered, ok := noNone.(*UnionType)
+	require.True(t, ok)
+	assert.Len(t, filtered.ElementTypes, 3)
+	assert.NotContains(t, filtered.ElementTypes, NoneType)
+
+	// Keeping everything returns the union unchanged.
+	assert.Same(t, union, union.Filter(func(Type) bool { return true }).(*UnionType))
+
+	// A single surviving member collapses to the member itself.
+	assert.Equal(t, BoolType, union.Filter(func(e Type) bool { return e == BoolType }))
+
+	// Filtering out every member returns NoneType.
+	assert.Equal(t, NoneType, union.Filter(func(Type) bool { return false }))
+
+	// Per-member annotations follow surviving members.
+	dep := DeprecationAnnotation{Message: "gone"}
+	annotated := NewUnionTypeAnnotatedMembers(
+		[]Type{StringType, IntType, BoolType},
+		[][]any{nil, {dep}, nil},
+	).(*UnionType)
+	kept := annotated.Filter(func(e Type) bool { return e != BoolType }).(*UnionType)
+	found := false
+	for i, e := range kept.ElementTypes {
+		if e.Equals(IntType) {
+			assert.Equal(t, []any{dep}, kept.MemberAnnotations(i))
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestUnifyOptionalTypes(t *testing.T) {
+	t.Parallel()
+
+	// int converts safely to number, so the unification is safe and stays optional.
+	result, kind := NewOptionalType(IntType).(*UnionType).unify(NewOptionalType(NumberType))
+	assert.Equal(t, SafeConversion, kind)
+	assert.True(t, result.Equals(NewOptionalType(NumberType)))
+
+	// int also converts safely to string.
+	result, kind = NewOptionalType(IntType).(*UnionType).unify(NewOptionalType(StringType))
+	assert.Equal(t, SafeConversion, kind)
+	assert.True(t, result.Equals(NewOptionalType(StringType)))
+
+	// bool and number only unify unsafely; the result preserves optionality and reports
+	// the inner conversion kind rather than the safe conversion implied by unioning all
+	// of the members together.
+	result, kind = NewOptionalType(BoolType).(*UnionType).unify(NewOptionalType(NumberType))
+	assert.Equal(t, UnsafeConversion, kind)
+	assert.True(t, result.Equals(NewOptionalType(NumberType)))
+}
+
+func TestUnionConvertibleMembers(t *testing.T) {
+	t.Parallel()
+
+	union := NewUnionType(StringType, NumberType, BoolType).(*UnionType)
+
+	// int converts safely to string and number, but only unsafely to bool.
+	safe := union.ConvertibleMembers(IntType)
+	require.Len(t, safe, 2)
+	assert.Contains(t, safe, StringType)
+	assert.Contains(t, safe, NumberType)
+
+	unsafe := union.UnsafeConvertibleMembers(IntType)
+	assert.Equal(t, []Type{BoolType}, unsafe)
+
+	// A type with no relationship to the members converts to none of them.
+	assert.Empty(t, union.ConvertibleMembers(NewObjectType(map[string]Type{"x": IntType})))
+}
+
+func TestUnionCanonicalizeOrdersNoneLast(t *testing.T) {
+	t.Parallel()
+
+	// Alphabetical sorting places none between int and string.
+	union := NewUnionType(StringType, NoneType, BoolType, IntType).(*UnionType)
+	require.NotEqual(t, NoneType, union.ElementTypes[len(union.ElementTypes)-1])
+
+	canonical := union.Canonicalize()
+	require.Len(t, canonical.ElementTypes, 4)
+	assert.Equal(t, NoneType, canonical.ElementTypes[len(canonical.ElementTypes)-1])
+	assert.Equal(t, []Type{BoolType, IntType, StringType, NoneType}, canonical.ElementTypes)
+
+	// Canonicalization is idempotent and a no-op for unions without None.
+	assert.Same(t, canonical, canonical.Canonicalize())
+	noNone := NewUnionType(StringType, IntType).(*UnionType)
+	assert.Same(t, noNone, noNone.Canonicalize())
+
+	// Member annotations follow their members across the reorder.
+	dep := DeprecationAnnotation{Message: "gone"}
+	annotated := NewUnionTypeAnnotatedMembers(
+		[]Type{StringType, NoneType, IntType},
+		[][]any{{dep}, nil, nil},
+	).(*UnionType)
+	canonical = annotated.Canonicalize()
+	for i, e := range canonical.ElementTypes {
+		if e.Equals(StringType) {
+			assert.Equal(t, []any{dep}, canonical.MemberAnnotations(i))
+		} else {
+			assert.Nil(t, canonical.MemberAnnotations(i))
+		}
+	}
+}
+
+func TestUnionRejectsNullWithClearDiagnostic(t *testing.T) {
+	t.Parallel()
+
+	union := NewUnionType(StringType, IntType).(*UnionType)
+	kind, why := union.conversionFrom(NoneType, false, nil)
+	assert.Equal(t, NoConversion, kind)
+	require.NotNil(t, why)
+
+	diags := why()
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Summary, "value may be null but")
+	assert.Contains(t, diags[0].Summary, "is not optional")
+
+	// An optional union still accepts null.
+	optional := NewOptionalType(StringType)
+	assert.Equal(t, SafeConversion, optional.ConversionFrom(NoneType))
+}
+
+func TestUnionCanonicalString(t *testing.T) {
+	t.Parallel()
+
+	// Structurally equal unions render identically regardless of construction order or
+	// annotation pointer identity.
+	dep := DeprecationAnnotation{Message: "use v2"}
+	u1 := NewUnionTypeAnnotatedMembers(
+		[]Type{StringType, NoneType, IntType},
+		[][]any{{dep}, nil, nil},
+	).(*UnionType)
+	u2 := NewUnionTypeAnnotatedMembers(
+		[]Type{IntType, StringType, NoneType},
+		[][]any{nil, {DeprecationAnnotation{Message: "use v2"}}, nil},
+	).(*UnionType)
+
+	assert.Equal(t, u1.CanonicalString(), u2.CanonicalString())
+	assert.Contains(t, u1.CanonicalString(), "none")
+
+	// The None member renders last.
+	s := u1.CanonicalString()
+	assert.Less(t, strings.Index(s, "int"), strings.Index(s, "none"))
+	assert.Less(t, strings.Index(s, "string"), strings.Index(s, "none"))
+
+	// Unlike String, the canonical form is pointer-free: two equal unions may still have
+	// distinct String forms when annotated.
+	assert.NotContains(t, u1.CanonicalString(), "0x")
+}
+
+func TestMergeUnions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("optionals", func(t *testing.T) {
+		t.Parallel()
+
+		a := NewUnionTypeAnnotated([]Type{StringType, NoneType}, "docA").(*UnionType)
+		b := NewUnionTypeAnnotated([]Type{IntType, NoneType}, "docA", "docB").(*UnionType)
+
+		merged, ok := MergeUnions(a, b).(*UnionType)
+		require.True(t, ok)
+		assert.ElementsMatch(t, []Type{StringType, IntType, NoneType}, merged.ElementTypes)
+
+		// Shared annotations are deduplicated; the rest are concatenated.
+		assert.Equal(t, []any{"docA", "docB"}, merged.Annotations)
+	})
+
+	t.Run("disjoint", func(t *testing.T) {
+		t.Parallel()
+
+		a := NewUnionTypeAnnotated([]Type{BoolType, NumberType}, "x").(*UnionType)
+		b := NewUnionTypeAnnotated([]Type{StringType, IntType}, "y").(*UnionType)
+
+		merged, ok := MergeUnions(a, b).(*UnionType)
+		require.True(t, ok)
+		assert.ElementsMatch(t, []Type{BoolType, NumberType, StringType, IntType}, merged.ElementTypes)
+		assert.Equal(t, []any{"x", "y"}, merged.Annotations)
+	})
+}
+
+func TestUnionIsSubsetOf(t *testing.T) {
+	t.Parallel()
+
+	narrow := NewUnionType(StringType, IntType).(*UnionType)
+	broad := NewUnionType(StringType, IntType, NoneType).(*UnionType)
+	disjoint := NewUnionType(BoolType, NumberType).(*UnionType)
+
+	// Every member of the narrowed union is assignable to a member of the broader one.
+	assert.True(t, narrow.IsSubsetOf(broad))
+
+	// The broader union has a None member that the narrow one cannot absorb.
+	assert.False(t, broad.IsSubsetOf(narrow))
+
+	// Disjoint unions are subsets in neither direction.
+	assert.False(t, narrow.IsSubsetOf(disjoint))
+	assert.False(t, disjoint.IsSubsetOf(narrow))
+
+	// A union is always a subset of itself.
+	assert.True(t, narrow.IsSubsetOf(narrow))
+}
+
+func TestUnionFromGoTypes(t *testing.T) {
+	t.Parallel()
+
+	// A mix of primitives and slices maps to the union of their model types.
+	typ, err := UnionFromGoTypes(
+		reflect.TypeOf(""),
+		reflect.TypeOf(0),
+		reflect.TypeOf([]bool(nil)),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "union(int, list(bool), string)", typ.String())
+
+	// A single type is returned as itself, mirroring NewUnionType.
+	typ, err = UnionFromGoTypes(reflect.TypeOf(3.14))
+	require.NoError(t, err)
+	assert.Equal(t, NumberType, typ)
+
+	// Pointers become optionals and string-keyed maps become map types.
+	typ, err = UnionFromGoTypes(reflect.TypeOf((*string)(nil)), reflect.TypeOf(map[string]int(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, "union(map(int), none, string)", typ.String())
+
+	// Unmappable types produce a clear error.
+	_, err = UnionFromGoTypes(reflect.TypeOf(""), reflect.TypeOf(make(chan int)))
+	assert.ErrorContains(t, err, "cannot map Go type chan int")
+
+	_, err = UnionFromGoTypes()
+	assert.ErrorContains(t, err, "at least one type")
+}
+
+func TestUnionSafelyConvertibleFrom(t *testing.T) {
+	t.Parallel()
+
+	union := NewUnionType(NumberType, BoolType).(*UnionType)
+
+	// Ints convert safely to the number member.
+	assert.True(t, union.SafelyConvertibleFrom(IntType))
+
+	// Numbers only convert unsafely into union(int, bool): narrowing to int may lose
+	// precision, so the predicate rejects it.
+	narrowing := NewUnionType(IntType, BoolType).(*UnionType)
+	assert.Equal(t, UnsafeConversion, narrowing.ConversionFrom(NumberType))
+	assert.False(t, narrowing.SafelyConvertibleFrom(NumberType))
+
+	// No member converts at all.
+	assert.False(t, narrowing.SafelyConvertibleFrom(NewListType(StringType)))
+}
@@ -56,8 +56,15 @@ type generator struct {
 	// Maps top-level PCL node names to unique Python identifiers.
 	nodeIdentifiers map[string]string
 
-	configCreated           bool
-	quotes                  map[model.Expression]string
+	configCreated bool
+	quotes        map[model.Expression]string
+	// sharedStringTemps maps lifted string-literal contents to the temp created for their
+	// first top-level occurrence, so identical long strings are hoisted once per program
+	// and referenced everywhere instead of producing a temp per expression.
+	sharedStringTemps map[string]*quoteTemp
+	// tempCounter numbers lifted temps program-wide so a shared temp is never shadowed by
+	// a later statement-local temp reusing its name.
+	tempCounter             int
 	isComponent             bool
 	deferredOutputVariables []*pcl.DeferredOutputVariable
 
@@ -478,8 +485,9 @@ func newGenerator(program *pcl.Program) (*generator, error) {
 	}
 
 	g := &generator{
-		program: program,
-		quotes:  map[model.Expression]string{},
+		program:           program,
+		quotes:            map[model.Expression]string{},
+		sharedStringTemps: map[string]*quoteTemp{},
 	}
 	g.Formatter = format.NewFormatter(g)
 
@@ -701,6 +709,16 @@ func (g *generator) genPreamble(w io.Writer, program *pcl.Program, preambleHelpe
 			}
 		}
 		diags := n.VisitExpressions(nil, func(n model.Expression) (model.Expression, hcl.Diagnostics) {
+			switch x := n.(type) {
+			case *model.ScopeTraversalExpression:
+				if traversalHasUnionReceiver(x.Traversal.SimpleSplit().Rel, x.Parts) {
+					preambleHelperMethods.Add(unionMemberGetHelper(g.Indent))
+				}
+			case *model.RelativeTraversalExpression:
+				if traversalHasUnionReceiver(x.Traversal, x.Parts) {
+					preambleHelperMethods.Add(unionMemberGetHelper(g.Indent))
+				}
+			}
 			if call, ok := n.(*model.FunctionCallExpression); ok {
 				if call.Name == pcl.Invoke {
 					pkg, _, _, invokeDiags := functionName(call.Args[0])
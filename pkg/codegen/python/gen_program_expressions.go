@@ -749,7 +749,7 @@ func escapeRune(c rune) string {
 }
 
 //nolint:errcheck
-func (g *generator) genEscapedString(w runeWriter, v string, escapeNewlines, escapeBraces bool) {
+func genEscapedString(w runeWriter, v string, escapeNewlines, escapeBraces bool) {
 	for _, c := range v {
 		switch c {
 		case '\n':
@@ -788,14 +788,35 @@ func (g *generator) genEscapedString(w runeWriter, v string, escapeNewlines, esc
 }
 
 func (g *generator) genStringLiteral(w io.Writer, quotes, v string) {
-	builder := &strings.Builder{}
+	g.Fgenf(w, "%s", quoteStringWith(quotes, v))
+}
 
+// genBytesLiteral emits a Python bytes literal (e.g. b"\x00ab") for the raw bytes of v using
+// the given prefixed quote style (`b"` or `b'`). Printable ASCII passes through unescaped;
+// the quote character and backslash are backslash-escaped; everything else is emitted as \xNN.
+func (g *generator) genBytesLiteral(w io.Writer, quotes, v string) {
+	if quotes == "" {
+		quotes = `b"`
+	}
+	quote := quotes[len(quotes)-1]
+
+	builder := &strings.Builder{}
 	builder.WriteString(quotes)
-	escapeNewlines := quotes == `"` || quotes == `'`
-	g.genEscapedString(builder, v, escapeNewlines, false)
-	builder.WriteString(quotes)
+	for i := 0; i < len(v); i++ {
+		b := v[i]
+		switch {
+		case b == quote || b == '\\':
+			builder.WriteByte('\\')
+			builder.WriteByte(b)
+		case b >= 0x20 && b < 0x7f:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(builder, `\x%02x`, b)
+		}
+	}
+	builder.WriteByte(quote)
 
-	g.Fgenf(w, "%s", builder.String())
+	g.Fgen(w, builder.String())
 }
 
 func (g *generator) GenLiteralValueExpression(w io.Writer, expr *model.LiteralValueExpression) {
@@ -804,6 +825,11 @@ func (g *generator) GenLiteralValueExpression(w io.Writer, expr *model.LiteralVa
 		typ = cns.Type
 	}
 
+	if BytesType.Equals(typ) {
+		g.genBytesLiteral(w, g.quotes[expr], expr.Value.AsString())
+		return
+	}
+
 	switch typ {
 	case model.BoolType:
 		if expr.Value.True() {
@@ -917,8 +943,16 @@ func (g *generator) genObjectConsExpression(w io.Writer, expr *model.ObjectConsE
 	}
 }
 
-func (g *generator) genRelativeTraversal(w io.Writer, traversal hcl.Traversal, parts []model.Traversable) {
-	for _, traverser := range traversal {
+// genRelativeTraversal renders the given traversal onto the already-rendered receiver
+// expression. When a step's receiver is a union type, the member the value takes at runtime
+// may be dict-backed or object-backed (or None for optional unions), so a plain attribute
+// access can throw. In that case we emit a call to the union_member_get preamble helper,
+// which narrows via isinstance and falls back to getattr; the call form evaluates the
+// receiver once, where an inline guard would repeat it (and nest exponentially for chained
+// traversals).
+func (g *generator) genRelativeTraversal(w io.Writer, receiver string, traversal hcl.Traversal, parts []model.Traversable) {
+	recv := receiver
+	for i, traverser := range traversal {
 		var key cty.Value
 		switch traverser := traverser.(type) {
 		case hcl.TraverseAttr:
@@ -929,27 +963,70 @@ func (g *generator) genRelativeTraversal(w io.Writer, traversal hcl.Traversal, p
 			contract.Failf("unexpected traverser of type %T (%v)", traverser, traverser.SourceRange())
 		}
 
+		unionReceiver := false
+		if i < len(parts) {
+			receiverType := model.ResolveOutputs(model.GetTraversableType(parts[i]))
+			_, unionReceiver = receiverType.(*model.UnionType)
+		}
+
 		switch key.Type() {
 		case cty.String:
 			keyVal := PyName(key.AsString())
 			contract.Assertf(isLegalIdentifier(keyVal), "illegal identifier: %q", keyVal)
-			g.Fgenf(w, ".%s", keyVal)
+			if unionReceiver {
+				// Single-quote the key: the call frequently renders inside a "-quoted
+				// f-string, which may not contain its own delimiter before Python 3.12.
+				// keyVal needs no escaping; it is asserted to be a legal identifier above.
+				recv = fmt.Sprintf("union_member_get(%s, '%s')", recv, keyVal)
+			} else {
+				recv = fmt.Sprintf("%s.%s", recv, keyVal)
+			}
 		case cty.Number:
 			idx, _ := key.AsBigFloat().Int64()
-			g.Fgenf(w, "[%d]", idx)
+			recv = fmt.Sprintf("%s[%d]", recv, idx)
 		default:
 			keyExpr := &model.LiteralValueExpression{Value: key}
 			diags := keyExpr.Typecheck(false)
 			contract.Ignore(diags)
 
-			g.Fgenf(w, "[%v]", keyExpr)
+			var index bytes.Buffer
+			g.Fgenf(&index, "[%v]", keyExpr)
+			recv += index.String()
 		}
 	}
+	g.Fgen(w, recv)
+}
+
+// traversalHasUnionReceiver reports whether any string-keyed step in the traversal is
+// applied to a union-typed receiver, i.e. whether rendering it needs the union_member_get
+// preamble helper. The step receivers line up with parts the same way they do in
+// genRelativeTraversal.
+func traversalHasUnionReceiver(traversal hcl.Traversal, parts []model.Traversable) bool {
+	for i, traverser := range traversal {
+		var key cty.Value
+		switch traverser := traverser.(type) {
+		case hcl.TraverseAttr:
+			key = cty.StringVal(traverser.Name)
+		case hcl.TraverseIndex:
+			key = traverser.Key
+		default:
+			continue
+		}
+		if key.Type() != cty.String || i >= len(parts) {
+			continue
+		}
+		receiverType := model.ResolveOutputs(model.GetTraversableType(parts[i]))
+		if _, ok := receiverType.(*model.UnionType); ok {
+			return true
+		}
+	}
+	return false
 }
 
 func (g *generator) GenRelativeTraversalExpression(w io.Writer, expr *model.RelativeTraversalExpression) {
-	g.Fgenf(w, "%.16v", expr.Source)
-	g.genRelativeTraversal(w, expr.Traversal, expr.Parts)
+	var source bytes.Buffer
+	g.Fgenf(&source, "%.16v", expr.Source)
+	g.genRelativeTraversal(w, source.String(), expr.Traversal, expr.Parts)
 }
 
 func (g *generator) GenScopeTraversalExpression(w io.Writer, expr *model.ScopeTraversalExpression) {
@@ -997,8 +1074,7 @@ func (g *generator) GenScopeTraversalExpression(w io.Writer, expr *model.ScopeTr
 		rootName = "__item"
 	}
 
-	g.Fgen(w, rootName)
-	g.genRelativeTraversal(w, expr.Traversal.SimpleSplit().Rel, expr.Parts)
+	g.genRelativeTraversal(w, rootName, expr.Traversal.SimpleSplit().Rel, expr.Parts)
 }
 
 func isHookArgsTraversal(expr *model.ScopeTraversalExpression) bool {
@@ -1046,7 +1122,7 @@ func (g *generator) GenTemplateExpression(w io.Writer, expr *model.TemplateExpre
 	g.Fprintf(b, "%s%s", prefix, quotes)
 	for _, expr := range expr.Parts {
 		if lit, ok := expr.(*model.LiteralValueExpression); ok && model.StringType.AssignableFrom(lit.Type()) {
-			g.genEscapedString(b, lit.Value.AsString(), escapeNewlines, escapeBraces)
+			genEscapedString(b, lit.Value.AsString(), escapeNewlines, escapeBraces)
 		} else {
 			g.Fgenf(b, "{%.v}", expr)
 		}
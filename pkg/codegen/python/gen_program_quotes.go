@@ -138,6 +138,88 @@ func (g *generator) rewriteTraversal(traversal hcl.Traversal, source model.Expre
 	return currentExpression
 }
 
+// QuoteStyle selects the quoting used by QuoteString.
+type QuoteStyle int
+
+const (
+	// QuoteDouble produces a double-quoted literal: "...".
+	QuoteDouble QuoteStyle = iota
+	// QuoteSingle produces a single-quoted literal: '...'.
+	QuoteSingle
+	// QuoteLongDouble produces a triple-double-quoted literal: """...""".
+	QuoteLongDouble
+	// QuoteLongSingle produces a triple-single-quoted literal: '''...'''.
+	QuoteLongSingle
+)
+
+func (q QuoteStyle) quotes() (string, error) {
+	switch q {
+	case QuoteDouble:
+		return `"`, nil
+	case QuoteSingle:
+		return `'`, nil
+	case QuoteLongDouble:
+		return `"""`, nil
+	case QuoteLongSingle:
+		return `'''`, nil
+	default:
+		return "", fmt.Errorf("unrecognized quote style %v", int(q))
+	}
+}
+
+// QuoteString returns a properly-quoted, properly-escaped Python string literal for s using
+// the preferred quote style. Short styles escape embedded newlines, quotes, and backslashes;
+// long (triple-quoted) styles keep their contents literal, so an error is returned if s
+// cannot be represented in the requested long style (i.e. it contains the closing quote
+// sequence or ends with its quote character).
+func QuoteString(s string, prefer QuoteStyle) (string, error) {
+	quotes, err := prefer.quotes()
+	if err != nil {
+		return "", err
+	}
+	if len(quotes) > 1 && (strings.Contains(s, quotes) || strings.HasSuffix(s, quotes[:1])) {
+		return "", fmt.Errorf("string cannot be represented as a %s-quoted literal", quotes)
+	}
+	lit := quoteStringWith(quotes, s)
+	if quotes == `'` {
+		// The escaper targets double-quoted output: it escapes `"` and leaves `'` alone. Flip
+		// both for a single-quoted literal. The replacements cannot misfire: an unescaped `"`
+		// never appears in the escaped body, and escape sequences never produce `'`.
+		body := lit[1 : len(lit)-1]
+		body = strings.ReplaceAll(body, `\"`, `"`)
+		body = strings.ReplaceAll(body, `'`, `\'`)
+		lit = quotes + body + quotes
+	}
+	return lit, nil
+}
+
+// quoteStringWith escapes v for the given quotes and returns the complete literal. It is the
+// lenient core of QuoteString, shared with the program generator's string emission.
+func quoteStringWith(quotes, v string) string {
+	builder := &strings.Builder{}
+
+	builder.WriteString(quotes)
+	escapeNewlines := quotes == `"` || quotes == `'`
+	genEscapedString(builder, v, escapeNewlines, false)
+	builder.WriteString(quotes)
+
+	return builder.String()
+}
+
+// BytesType identifies literals that should be emitted as Python bytes literals (b"...").
+// PCL has no first-class bytes type, so producers of base64/bytes values mark the literal's
+// type with this opaque type to opt into bytes emission.
+var BytesType = model.NewOpaqueType("bytes")
+
+// isBytesLiteral returns true if x is a literal that should be emitted as a bytes literal.
+func isBytesLiteral(x *model.LiteralValueExpression) bool {
+	typ := x.Type()
+	if cns, ok := typ.(*model.ConstType); ok {
+		typ = cns.Type
+	}
+	return BytesType.Equals(typ)
+}
+
 type quoteTemp struct {
 	Name         string
 	VariableType model.Type
@@ -162,19 +244,33 @@ type quoteAllocations struct {
 }
 
 type quoteAllocator struct {
+	g           *generator
 	allocations *quoteAllocations
 	allocated   codegen.StringSet
 	stack       []model.Expression
 }
 
-func (qa *quoteAllocator) allocate(longString bool) (string, bool) {
+// longStyleFits reports whether contents can appear literally inside the given triple-quote
+// delimiter. Long strings are emitted without escaping, so a value that contains the closing
+// sequence or ends with its quote character cannot use that style (the same rules QuoteString
+// enforces).
+func longStyleFits(quotes string, contents []string) bool {
+	for _, v := range contents {
+		if strings.Contains(v, quotes) || strings.HasSuffix(v, quotes[:1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (qa *quoteAllocator) allocate(longString bool, contents []string) (string, bool) {
 	if longString {
-		if !qa.allocated.Has(`"`) && !qa.allocated.Has(`"""`) {
+		if !qa.allocated.Has(`"`) && !qa.allocated.Has(`"""`) && longStyleFits(`"""`, contents) {
 			qa.allocated.Add(`"""`)
 			return `"""`, true
 		}
 
-		if !qa.allocated.Has(`'`) && !qa.allocated.Has(`'''`) {
+		if !qa.allocated.Has(`'`) && !qa.allocated.Has(`'''`) && longStyleFits(`'''`, contents) {
 			qa.allocated.Add(`'''`)
 			return `'''`, true
 		}
@@ -195,6 +291,24 @@ func (qa *quoteAllocator) allocate(longString bool) (string, bool) {
 	return "", false
 }
 
+// allocateBytes allocates a quote style for a bytes literal. Bytes styles are tracked under
+// their prefixed spellings (`b"` and `b'`), but each also avoids the matching short string
+// quote: before Python 3.12, an f-string may not contain its own delimiter anywhere, so a
+// bytes literal nested in a "-quoted f-string must use b'...' (and vice versa).
+func (qa *quoteAllocator) allocateBytes() (string, bool) {
+	if !qa.allocated.Has(`b"`) && !qa.allocated.Has(`"`) {
+		qa.allocated.Add(`b"`)
+		return `b"`, true
+	}
+
+	if !qa.allocated.Has(`b'`) && !qa.allocated.Has(`'`) {
+		qa.allocated.Add(`b'`)
+		return `b'`, true
+	}
+
+	return "", false
+}
+
 func (qa *quoteAllocator) free(quotes string) {
 	qa.allocated.Delete(quotes)
 }
@@ -207,16 +321,52 @@ func (qa *quoteAllocator) inTemplate() bool {
 	return isTemplate
 }
 
+// sharedStringKey returns the contents under which a lifted string expression may be shared
+// across statements, and whether the expression is a shareable constant. String literals
+// qualify, as do templates whose parts are all string literals — the form quoted PCL strings
+// bind to — since both render to the same text for the same contents. Any expression with a
+// non-constant part is not shareable.
+func sharedStringKey(x model.Expression) (string, bool) {
+	switch x := x.(type) {
+	case *model.LiteralValueExpression:
+		if model.StringType.AssignableFrom(x.Type()) {
+			return x.Value.AsString(), true
+		}
+	case *model.TemplateExpression:
+		builder := &strings.Builder{}
+		for _, part := range x.Parts {
+			lit, ok := part.(*model.LiteralValueExpression)
+			if !ok || !model.StringType.AssignableFrom(lit.Type()) {
+				return "", false
+			}
+			builder.WriteString(lit.Value.AsString())
+		}
+		return builder.String(), true
+	}
+	return "", false
+}
+
 func (qa *quoteAllocator) allocateExpression(x model.Expression) (model.Expression, hcl.Diagnostics) {
 	qa.stack = append(qa.stack, x)
 
 	var longString bool
+	var contents []string
 	switch x := x.(type) {
 	case *model.LiteralValueExpression:
+		if isBytesLiteral(x) {
+			// Bytes literals always use a short style: short styles escape anything, so there is
+			// no contention to resolve by lifting to a temp. If both bytes styles are somehow in
+			// use, emission falls back to b"..." with the embedded quotes escaped.
+			if quote, ok := qa.allocateBytes(); ok {
+				qa.allocations.quotes[x] = quote
+			}
+			return x, nil
+		}
 		if !model.StringType.AssignableFrom(x.Type()) || qa.inTemplate() {
 			return x, nil
 		}
 		v := x.Value.AsString()
+		contents = []string{v}
 		switch strings.Count(v, "\n") {
 		case 0:
 			// OK
@@ -229,6 +379,10 @@ func (qa *quoteAllocator) allocateExpression(x model.Expression) (model.Expressi
 		for i, part := range x.Parts {
 			if lit, ok := part.(*model.LiteralValueExpression); ok && model.StringType.AssignableFrom(lit.Type()) {
 				v := lit.Value.AsString()
+				contents = append(contents, v)
+				if longString {
+					continue
+				}
 				switch strings.Count(v, "\n") {
 				case 0:
 					continue
@@ -238,27 +392,52 @@ func (qa *quoteAllocator) allocateExpression(x model.Expression) (model.Expressi
 					}
 				}
 				longString = true
-				break
 			}
 		}
 	default:
 		return x, nil
 	}
 
-	if quote, ok := qa.allocate(longString); ok {
+	if quote, ok := qa.allocate(longString, contents); ok {
 		qa.allocations.quotes[x] = quote
 		return x, nil
 	}
 
-	allocator := &quoteAllocator{allocated: codegen.StringSet{}, allocations: qa.allocations}
+	// If the contents themselves cannot be represented in any long style, a temp won't help:
+	// fall back to a short style, which escapes newlines and keeps the value inline.
+	if longString && !longStyleFits(`"""`, contents) && !longStyleFits(`'''`, contents) {
+		if quote, ok := qa.allocate(false, contents); ok {
+			qa.allocations.quotes[x] = quote
+			return x, nil
+		}
+	}
+
+	// Identical string constants lifted at top level are hoisted once per program: reuse the
+	// temp created for a previous occurrence instead of emitting a duplicate assignment.
+	key, isStringConst := sharedStringKey(x)
+	shareable := isStringConst && qa.g != nil && qa.g.Indent == ""
+	if shareable {
+		if temp, ok := qa.g.sharedStringTemps[key]; ok {
+			return &model.ScopeTraversalExpression{
+				RootName:  temp.Name,
+				Traversal: hcl.Traversal{hcl.TraverseRoot{Name: ""}},
+				Parts:     []model.Traversable{temp},
+			}, nil
+		}
+	}
+
+	allocator := &quoteAllocator{g: qa.g, allocated: codegen.StringSet{}, allocations: qa.allocations}
 	value, valueDiags := model.VisitExpression(x, allocator.allocateExpression, allocator.freeExpression)
 
 	temp := &quoteTemp{
-		Name:         fmt.Sprintf("str%d", len(qa.allocations.temps)),
+		Name:         qa.tempName(),
 		VariableType: x.Type(),
 		Value:        value,
 	}
 	qa.allocations.temps = append(qa.allocations.temps, temp)
+	if shareable {
+		qa.g.sharedStringTemps[key] = temp
+	}
 
 	return &model.ScopeTraversalExpression{
 		RootName:  temp.Name,
@@ -267,6 +446,17 @@ func (qa *quoteAllocator) allocateExpression(x model.Expression) (model.Expressi
 	}, valueDiags
 }
 
+// tempName allocates the next lifted-temp name. Names are numbered program-wide when a
+// generator is attached so a shared temp is never shadowed by a later statement-local one.
+func (qa *quoteAllocator) tempName() string {
+	if qa.g == nil {
+		return fmt.Sprintf("str%d", len(qa.allocations.temps))
+	}
+	name := fmt.Sprintf("str%d", qa.g.tempCounter)
+	qa.g.tempCounter++
+	return name
+}
+
 func (qa *quoteAllocator) freeExpression(x model.Expression) (model.Expression, hcl.Diagnostics) {
 	defer func() {
 		qa.stack = qa.stack[:len(qa.stack)-1]
@@ -274,6 +464,12 @@ func (qa *quoteAllocator) freeExpression(x model.Expression) (model.Expression,
 
 	switch x := x.(type) {
 	case *model.LiteralValueExpression:
+		if isBytesLiteral(x) {
+			if quotes, ok := qa.allocations.quotes[x]; ok {
+				qa.free(quotes)
+			}
+			return x, nil
+		}
 		if !model.StringType.AssignableFrom(x.Type()) || qa.inTemplate() {
 			return x, nil
 		}
@@ -312,7 +508,7 @@ func (g *generator) rewriteQuotes(x model.Expression) (model.Expression, []*quot
 	allocations := &quoteAllocations{
 		quotes: g.quotes,
 	}
-	allocator := &quoteAllocator{allocated: codegen.StringSet{}, allocations: allocations}
+	allocator := &quoteAllocator{g: g, allocated: codegen.StringSet{}, allocations: allocations}
 	x, rewriteDiags2 := model.VisitExpression(x, allocator.allocateExpression, allocator.freeExpression)
 
 	diagnostics := slices.Concat(rewriteDiags1, rewriteDiags2)
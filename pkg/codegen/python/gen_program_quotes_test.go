@@ -15,13 +15,16 @@
 package python
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
 	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestLowerPropertyAccess(t *testing.T) {
@@ -70,3 +73,88 @@ resource rta "infra:index:RouteTableAssociation" {
 	x.SetTrailingTrivia(nil)
 	assert.Equal(t, "vpcSubnet[range[key]].id", fmt.Sprintf("%v", x))
 }
+
+func TestQuoteString(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input    string
+		prefer   QuoteStyle
+		expected string
+	}{
+		{`plain`, QuoteDouble, `"plain"`},
+		{`has "quotes"`, QuoteDouble, `"has \"quotes\""`},
+		{`has "quotes"`, QuoteSingle, `'has "quotes"'`},
+		{`it's`, QuoteSingle, `'it\'s'`},
+		{"line1\nline2", QuoteDouble, `"line1\nline2"`},
+		{"line1\nline2", QuoteLongDouble, "\"\"\"line1\nline2\"\"\""},
+		{`back\slash`, QuoteDouble, `"back\\slash"`},
+		{"multi\nline", QuoteLongSingle, "'''multi\nline'''"},
+	}
+	for _, c := range cases {
+		actual, err := QuoteString(c.input, c.prefer)
+		require.NoError(t, err)
+		assert.Equal(t, c.expected, actual, "input %q with style %v", c.input, c.prefer)
+	}
+
+	// Strings that contain the closing sequence or end with the quote character cannot be
+	// represented as long strings.
+	_, err := QuoteString(`embedded """ quotes`, QuoteLongDouble)
+	assert.Error(t, err)
+	_, err = QuoteString(`trailing quote"`, QuoteLongDouble)
+	assert.Error(t, err)
+	_, err = QuoteString("fine", QuoteStyle(42))
+	assert.Error(t, err)
+}
+
+func TestGenBytesLiteral(t *testing.T) {
+	t.Parallel()
+
+	newTestGenerator := func(t *testing.T) *generator {
+		program, diags := parseAndBindProgram(t, "x = 1\n", "bytes_literal.pp")
+		contract.Ignore(diags)
+		g, err := newGenerator(program)
+		require.NoError(t, err)
+		return g
+	}
+
+	t.Run("outside a template", func(t *testing.T) {
+		t.Parallel()
+
+		g := newTestGenerator(t)
+		x := model.NewTypedLiteralValueExpression(cty.StringVal("ab\x00\x7f\"\\c"), BytesType)
+
+		rewritten, temps, diags := g.rewriteQuotes(x)
+		require.Empty(t, diags)
+		require.Empty(t, temps)
+
+		var buf bytes.Buffer
+		g.Fgenf(&buf, "%v", rewritten)
+		assert.Equal(t, `b"ab\x00\x7f\"\\c"`, buf.String())
+	})
+
+	t.Run("inside a template", func(t *testing.T) {
+		t.Parallel()
+
+		g := newTestGenerator(t)
+		x := &model.TemplateExpression{
+			Parts: []model.Expression{
+				&model.LiteralValueExpression{Value: cty.StringVal("prefix-")},
+				model.NewTypedLiteralValueExpression(cty.StringVal("\x01"), BytesType),
+			},
+		}
+		// Typecheck the template itself (but not its parts, which would recompute the bytes
+		// literal's type from its value).
+		require.Empty(t, x.Typecheck(false))
+
+		rewritten, temps, diags := g.rewriteQuotes(x)
+		require.Empty(t, diags)
+		require.Empty(t, temps)
+
+		var buf bytes.Buffer
+		g.Fgenf(&buf, "%v", rewritten)
+		// The bytes literal switches to b'...': before Python 3.12 an f-string may not contain
+		// its own delimiter, so b"..." is not available inside the "-quoted template.
+		assert.Equal(t, `f"prefix-{b'\x01'}"`, buf.String())
+	})
+}
@@ -15,6 +15,8 @@
 package python
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
@@ -50,3 +52,119 @@ func TestFunctionInvokeBindsArgumentObjectType(t *testing.T) {
 		}
 	}
 }
+
+func TestGenUnionTraversalGuard(t *testing.T) {
+	t.Parallel()
+
+	// "u" binds to a union type, so member access must emit a guard that is safe for
+	// both dict-backed and object-backed members of the union.
+	const source = `u = true ? { foo = "bar" } : "nope"
+out = u.foo
+`
+
+	program, diags := parseAndBindProgram(t, source, "union_traversal.pp")
+	contract.Ignore(diags)
+
+	g, err := newGenerator(program)
+	require.NoError(t, err)
+
+	var out *pcl.LocalVariable
+	for _, n := range g.program.Nodes {
+		if v, ok := n.(*pcl.LocalVariable); ok && v.Name() == "out" {
+			out = v
+			break
+		}
+	}
+	require.NotNil(t, out)
+
+	var buf bytes.Buffer
+	g.Fgenf(&buf, "%v", out.Definition.Value)
+	assert.Equal(t, `union_member_get(u, 'foo')`, buf.String())
+
+	// Generating the whole program emits the helper the guard calls into the preamble.
+	files, genDiags, err := GenerateProgram(program)
+	require.NoError(t, err)
+	assert.False(t, genDiags.HasErrors())
+	assert.Contains(t, string(files["__main__.py"]), "def union_member_get(obj, key):")
+}
+
+func TestGenResourceLiftsUnquotableStringArg(t *testing.T) {
+	t.Parallel()
+
+	// The user_data value nests multi-line strings three levels deep, which exhausts both
+	// long quote styles at the innermost literal. That literal is lifted into a temp emitted
+	// before the resource call; the argument itself stays inline as a keyword argument.
+	const source = `resource web "infra:index:Instance" {
+	ami = "ami-123"
+	userData = "A\n${["B\n${["D\nE"][0]}\nC"][0]}\nZ"
+}
+`
+
+	program, diags := parseAndBindProgram(t, source, "lifted_string_arg.pp")
+	contract.Ignore(diags)
+
+	files, genDiags, err := GenerateProgram(program)
+	require.NoError(t, err)
+	assert.False(t, genDiags.HasErrors())
+
+	code := string(files["__main__.py"])
+	tempIdx := strings.Index(code, "str0 = ")
+	callIdx := strings.Index(code, "infra.Instance(")
+	require.NotEqual(t, -1, tempIdx, "expected a lifted temp in:\n%s", code)
+	require.NotEqual(t, -1, callIdx, "expected a resource call in:\n%s", code)
+	assert.Less(t, tempIdx, callIdx, "temps must be emitted before the resource call")
+	assert.Contains(t, code, "ami=\"ami-123\"")
+	assert.Contains(t, code, "user_data=")
+}
+
+func TestGenResourceMultilineStringArgFallsBackToShortQuotes(t *testing.T) {
+	t.Parallel()
+
+	// A multi-line string that contains both triple-quote sequences cannot use either long
+	// style; it must fall back to a short style with escaped newlines instead of being
+	// emitted as a broken long string.
+	const source = `resource web "infra:index:Instance" {
+	ami = "ami-123"
+	userData = "a\"\"\"b\n'''c"
+}
+`
+
+	program, diags := parseAndBindProgram(t, source, "short_quote_fallback.pp")
+	contract.Ignore(diags)
+
+	files, genDiags, err := GenerateProgram(program)
+	require.NoError(t, err)
+	assert.False(t, genDiags.HasErrors())
+
+	code := string(files["__main__.py"])
+	assert.Contains(t, code, `user_data="a\"\"\"b\n'''c"`)
+}
+
+func TestGenSharedLongStringTempAcrossResources(t *testing.T) {
+	t.Parallel()
+
+	// Both resources lift the same innermost multi-line literal. The temp is hoisted once at
+	// top level and shared rather than emitted before each resource call.
+	const source = `resource web "infra:index:Instance" {
+	ami = "ami-123"
+	userData = "A\n${["B\n${["D\nE"][0]}\nC"][0]}\nZ"
+}
+
+resource web2 "infra:index:Instance" {
+	ami = "ami-456"
+	userData = "A\n${["B\n${["D\nE"][0]}\nC"][0]}\nZ"
+}
+`
+
+	program, diags := parseAndBindProgram(t, source, "shared_string_temp.pp")
+	contract.Ignore(diags)
+
+	files, genDiags, err := GenerateProgram(program)
+	require.NoError(t, err)
+	assert.False(t, genDiags.HasErrors())
+
+	code := string(files["__main__.py"])
+	assert.Equal(t, 1, strings.Count(code, "str0 = "), "expected a single shared temp in:\n%s", code)
+	assert.NotContains(t, code, "str1", "expected the second occurrence to reuse the temp in:\n%s", code)
+	assert.Equal(t, 2, strings.Count(code, "{str0}"), "both resources should reference the shared temp in:\n%s", code)
+}
@@ -65,6 +65,18 @@ func getHelperMethodIfNeeded(functionName string, indent string) (string, bool)
 	}
 }
 
+// unionMemberGetHelper returns the body of a union_member_get helper function that reads a
+// property from a value whose static type is a union: dict-backed members are indexed,
+// object-backed members use getattr, and missing properties (or None receivers) yield None.
+func unionMemberGetHelper(indent string) string {
+	return fmt.Sprintf(
+		`%[1]sdef union_member_get(obj, key):
+%[1]s    if isinstance(obj, dict):
+%[1]s        return obj.get(key)
+%[1]s    return getattr(obj, key, None)
+`, indent)
+}
+
 // graphemeLengthHelper returns the body of a grapheme_length helper function that counts
 // Unicode grapheme clusters, matching PCL's length() semantics for strings.
 func graphemeLengthHelper(indent string) string {
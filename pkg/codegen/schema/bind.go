@@ -1504,6 +1504,16 @@ func (t *types) bindObjectTypeDetails(path string, obj *ObjectType, token string
 		return diags, err
 	}
 
+	var additionalProperties Type
+	if spec.AdditionalProperties != nil {
+		ap, apDiags, err := t.bindTypeSpec(path+"/additionalProperties", *spec.AdditionalProperties, false, options)
+		diags = diags.Extend(apDiags)
+		if err != nil {
+			return diags, err
+		}
+		additionalProperties = ap
+	}
+
 	language := makeLanguageMap(spec.Language)
 
 	obj.PackageReference = t.externalPackage()
@@ -1512,6 +1522,7 @@ func (t *types) bindObjectTypeDetails(path string, obj *ObjectType, token string
 	obj.Language = language
 	obj.Properties = properties
 	obj.properties = propertyMap
+	obj.AdditionalProperties = additionalProperties
 	obj.IsOverlay = spec.IsOverlay
 	obj.OverlaySupportedLanguages = spec.OverlaySupportedLanguages
 
@@ -1521,6 +1532,7 @@ func (t *types) bindObjectTypeDetails(path string, obj *ObjectType, token string
 	obj.InputShape.Language = language
 	obj.InputShape.Properties = inputProperties
 	obj.InputShape.properties = inputPropertyMap
+	obj.InputShape.AdditionalProperties = additionalProperties
 
 	return diags, nil
 }
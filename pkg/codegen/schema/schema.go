@@ -229,6 +229,9 @@ type ObjectType struct {
 	Comment string
 	// Properties is the list of the type's properties.
 	Properties []*Property
+	// AdditionalProperties, if set, is the element type of any dynamic properties not declared in Properties,
+	// marking the object type as open.
+	AdditionalProperties Type
 	// Language specifies additional language-specific data about the object type.
 	Language map[string]any
 	// IsOverlay indicates whether the type is an overlay provided by the package. Overlay code is generated by the
@@ -1342,6 +1345,10 @@ func (pkg *Package) marshalObject(t *ObjectType, plain bool) (ComplexTypeSpec, e
 	if err != nil {
 		return ComplexTypeSpec{}, err
 	}
+	if t.AdditionalProperties != nil {
+		ap := pkg.marshalType(t.AdditionalProperties, plain)
+		data.AdditionalProperties = &ap
+	}
 	return ComplexTypeSpec{ObjectTypeSpec: data}, nil
 }
 
@@ -1816,6 +1823,9 @@ type ObjectTypeSpec struct {
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 	// Properties, if present, is a map from property name to PropertySpec that describes the type's properties.
 	Properties map[string]PropertySpec `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// AdditionalProperties, if present, describes the element type of any dynamic properties not declared in
+	// Properties, marking the object type as open.
+	AdditionalProperties *TypeSpec `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
 	// Type must be "object" if this is an object type, or the underlying type for an enum.
 	Type string `json:"type,omitempty" yaml:"type,omitempty"`
 	// Required, if present, is a list of the names of an object type's required properties. These properties must be set
@@ -76,10 +76,21 @@ func RegisterInputType(interfaceType reflect.Type, input Input) {
 	if !interfaceType.Implements(inputType) {
 		panic(fmt.Errorf("expected %v to implement %v", interfaceType, inputType))
 	}
+	if input == nil {
+		panic(fmt.Errorf("expected a non-nil input implementation for %v", interfaceType))
+	}
 	concreteType := reflect.TypeOf(input)
 	if !concreteType.Implements(interfaceType) {
+		if reflect.PointerTo(concreteType).Implements(interfaceType) {
+			panic(fmt.Errorf("expected %v to implement interface %v: only *%v implements it; register a pointer",
+				concreteType, interfaceType, concreteType))
+		}
 		panic(fmt.Errorf("expected %v to implement interface %v", concreteType, interfaceType))
 	}
+	if elementType := input.ElementType(); elementType == nil {
+		panic(fmt.Errorf("the ElementType method of %v returned nil; it must return the input's element type",
+			concreteType))
+	}
 	existing, hasExisting := inputInterfaceTypeToConcreteType.LoadOrStore(interfaceType, concreteType)
 	if hasExisting {
 		panic(fmt.Errorf("an input type for %v is already registered: %v", interfaceType, existing))
@@ -268,6 +279,22 @@ func (o *OutputState) awaitOnce(ctx context.Context) (any, bool, bool, []Resourc
 
 	o.cond.L.Lock()
 	defer o.cond.L.Unlock()
+	if o.state == OutputPending && ctx.Done() != nil {
+		// Nothing broadcasts the condition variable when the context expires, so watch the
+		// context and wake the waiters below on cancellation. The watcher exits once this
+		// await completes.
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-ctx.Done():
+				o.cond.L.Lock()
+				o.cond.Broadcast()
+				o.cond.L.Unlock()
+			case <-stop:
+			}
+		}()
+	}
 	for o.state == OutputPending {
 		if ctx.Err() != nil {
 			return nil, true, false, nil, ctx.Err()
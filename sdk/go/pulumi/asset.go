@@ -16,7 +16,10 @@ package pulumi
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
@@ -140,3 +143,56 @@ func (a *archive) URI() string { return a.uri }
 func (a *archive) isArchive() {}
 
 func (a *archive) isAssetOrArchive() {}
+
+var (
+	// assetSchemes maps URI schemes to factories for custom Asset implementations.
+	assetSchemes sync.Map // string -> func(uri string) Asset
+	// archiveSchemes maps URI schemes to factories for custom Archive implementations.
+	archiveSchemes sync.Map // string -> func(uri string) Archive
+)
+
+// RegisterAssetScheme registers a factory for reconstructing remote assets whose URI uses the
+// given scheme (e.g. "myrepo" for "myrepo://..." URIs). When a URI-backed asset with a
+// registered scheme is unmarshaled, the factory is invoked with the full URI and its result is
+// used in place of the plain remote asset, enabling pluggable artifact backends. Panics if the
+// scheme is empty or already registered.
+func RegisterAssetScheme(scheme string, factory func(uri string) Asset) {
+	contract.Requiref(scheme != "", "scheme", "must not be empty")
+	contract.Requiref(factory != nil, "factory", "must not be nil")
+	if _, exists := assetSchemes.LoadOrStore(scheme, factory); exists {
+		panic(fmt.Errorf("an asset scheme factory for %q is already registered", scheme))
+	}
+}
+
+// RegisterArchiveScheme registers a factory for reconstructing remote archives whose URI uses
+// the given scheme. It is the archive analog of RegisterAssetScheme. Panics if the scheme is
+// empty or already registered.
+func RegisterArchiveScheme(scheme string, factory func(uri string) Archive) {
+	contract.Requiref(scheme != "", "scheme", "must not be empty")
+	contract.Requiref(factory != nil, "factory", "must not be nil")
+	if _, exists := archiveSchemes.LoadOrStore(scheme, factory); exists {
+		panic(fmt.Errorf("an archive scheme factory for %q is already registered", scheme))
+	}
+}
+
+// assetForURI reconstructs a URI-backed asset, consulting the registered scheme factories
+// before falling back to a plain remote asset.
+func assetForURI(uri string) Asset {
+	if scheme, _, ok := strings.Cut(uri, "://"); ok {
+		if factory, ok := assetSchemes.Load(scheme); ok {
+			return factory.(func(string) Asset)(uri)
+		}
+	}
+	return NewRemoteAsset(uri)
+}
+
+// archiveForURI reconstructs a URI-backed archive, consulting the registered scheme factories
+// before falling back to a plain remote archive.
+func archiveForURI(uri string) Archive {
+	if scheme, _, ok := strings.Cut(uri, "://"); ok {
+		if factory, ok := archiveSchemes.Load(scheme); ok {
+			return factory.(func(string) Archive)(uri)
+		}
+	}
+	return NewRemoteArchive(uri)
+}
@@ -31,6 +31,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/promise"
@@ -88,6 +89,9 @@ type contextState struct {
 	registeredOutputsLock    sync.Mutex   // a lock protecting the registeredOutputs map
 	registeredOutputs        map[URN]bool // tracks which resources have had outputs registered
 
+	capturedRegistrationsLock sync.Mutex             // a lock protecting capturedRegistrations
+	capturedRegistrations     []CapturedRegistration // registrations recorded when capture is enabled
+
 	join workGroup // the waitgroup for non-RPC async work associated with this context
 
 	packageRefs gsync.Map[string, *packageRefEntry] // per-context cache of parameterized provider package refs
@@ -98,6 +102,10 @@ type Context struct {
 	state *contextState
 	ctx   context.Context
 	Log   Log // the logging interface for the Pulumi log stream.
+
+	// outputTimeout bounds how long marshaling waits for any single output or URN to
+	// resolve. Zero waits indefinitely. See WithOutputTimeout.
+	outputTimeout time.Duration
 }
 
 // NewContext creates a fresh run context out of the given metadata.
@@ -265,14 +273,29 @@ func (ctx *Context) Context() context.Context {
 // WithValue returns a copy of base context in which the value associated with key is val.
 func (ctx *Context) WithValue(key, val any) *Context {
 	newCtx := &Context{
-		ctx:   ctx.ctx,
-		state: ctx.state,
-		Log:   ctx.Log,
+		ctx:           ctx.ctx,
+		state:         ctx.state,
+		Log:           ctx.Log,
+		outputTimeout: ctx.outputTimeout,
 	}
 	newCtx.ctx = context.WithValue(newCtx.ctx, key, val)
 	return newCtx
 }
 
+// WithOutputTimeout returns a copy of the context that bounds how long marshaling waits
+// for any single output or URN to resolve. By default marshaling waits indefinitely, so a
+// provider bug or a deadlock that leaves an output unresolved hangs the program; with a
+// timeout set, such waits instead fail with an error naming the property or resource
+// being awaited. A non-positive duration waits indefinitely.
+func (ctx *Context) WithOutputTimeout(d time.Duration) *Context {
+	return &Context{
+		ctx:           ctx.ctx,
+		state:         ctx.state,
+		Log:           ctx.Log,
+		outputTimeout: d,
+	}
+}
+
 // Value returns the value associated with key from base context
 func (ctx *Context) Value(key any) any {
 	return ctx.ctx.Value(key)
@@ -293,6 +316,26 @@ func (ctx *Context) Close() error {
 	return nil
 }
 
+// WaitForOutputs blocks until all in-flight output resolutions associated with this context
+// have drained, or until the given context is canceled. It is intended for test harnesses
+// that need deterministic teardown between cases to avoid leaking resolving goroutines;
+// normal programs should rely on the RunWithContext lifecycle instead, which performs this
+// wait as part of shutdown.
+func (ctx *Context) WaitForOutputs(waitCtx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		ctx.state.join.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-waitCtx.Done():
+		return waitCtx.Err()
+	}
+}
+
 // wait waits for all asynchronous work associated with this context to drain. RPCs may not be queued once wait
 // returns.
 func (ctx *Context) wait() error {
@@ -795,6 +838,8 @@ func (ctx *Context) invokePackageRaw(
 	}
 	resolvedArgs, _, err := marshalInputOptions(args, anyType, &marshalOptions{
 		ErrorOnOutput: true,
+		OutputTimeout: ctx.outputTimeout,
+		Context:       ctx.ctx,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("marshaling arguments: %w", err)
@@ -1077,6 +1122,8 @@ func (ctx *Context) CallPackage(
 			// This way, providers creating output instances based on `argDependencies` won't create
 			// outputs for properties that only contain resource references.
 			ExcludeResourceRefsFromDeps: ctx.state.keepResources,
+			OutputTimeout:               ctx.outputTimeout,
+			Context:                     ctx.ctx,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("marshaling args: %w", err)
@@ -1090,6 +1137,8 @@ func (ctx *Context) CallPackage(
 				// This way, providers creating output instances based on `argDependencies` won't create
 				// outputs for properties that only contain resource references.
 				ExcludeResourceRefsFromDeps: ctx.state.keepResources,
+				OutputTimeout:               ctx.outputTimeout,
+				Context:                     ctx.ctx,
 			})
 			if err != nil {
 				return nil, fmt.Errorf("marshaling __self__: %w", err)
@@ -1843,6 +1892,7 @@ func (ctx *Context) registerResource(
 		if err != nil {
 			return
 		}
+		ctx.captureRegistration(t, name, inputs.resolvedProps)
 
 		// initialize both aliases and aliasURNs slices as nil
 		// depending on supportsAliasSpecs flag, one of them will be populated
@@ -1976,6 +2026,52 @@ func (ctx *Context) RegisterResource(
 	return ctx.registerResource(t, name, props, resource, false /*remote*/, "" /* packageRef */, opts...)
 }
 
+// CapturedRegistration records the marshaled inputs a single RegisterResource call sent to
+// the engine. See WithCaptureRegistrations.
+type CapturedRegistration struct {
+	// Type is the resource's type token.
+	Type string
+	// Name is the resource's name.
+	Name string
+	// Inputs are the resource's marshaled input properties.
+	Inputs resource.PropertyMap
+}
+
+// CapturedRegistrations returns the registrations recorded so far. Registrations are
+// processed concurrently and program order is not observable, so the result is sorted by
+// type and then name to give replay tests a deterministic order. It returns nil unless
+// the context was created with WithCaptureRegistrations.
+func (ctx *Context) CapturedRegistrations() []CapturedRegistration {
+	s := ctx.state
+	s.capturedRegistrationsLock.Lock()
+	defer s.capturedRegistrationsLock.Unlock()
+	captured := slices.Clone(s.capturedRegistrations)
+	slices.SortFunc(captured, func(a, b CapturedRegistration) int {
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return captured
+}
+
+// captureRegistration records a registration's marshaled inputs when capture is enabled.
+// The implicit root stack registration is excluded: it is framework bookkeeping, not part
+// of what the program registered.
+func (ctx *Context) captureRegistration(t, name string, inputs resource.PropertyMap) {
+	s := ctx.state
+	if !s.info.captureRegistrations || t == "pulumi:pulumi:Stack" {
+		return
+	}
+	s.capturedRegistrationsLock.Lock()
+	defer s.capturedRegistrationsLock.Unlock()
+	s.capturedRegistrations = append(s.capturedRegistrations, CapturedRegistration{
+		Type:   t,
+		Name:   name,
+		Inputs: inputs.Copy(),
+	})
+}
+
 // It is recommended to use RegisterComponentResourceV2 instead which records component inputs to state.
 func (ctx *Context) RegisterComponentResource(
 	t, name string, resource ComponentResource, opts ...ResourceOption,
@@ -2620,6 +2716,9 @@ func (ctx *Context) prepareResourceInputs(res Resource, props Input, t string, o
 		// `propertyDependencies` won't create outputs for properties that only
 		// contain resource references.
 		ExcludeResourceRefsFromDeps: remote && ctx.state.keepResources,
+		OutputTimeout:               ctx.outputTimeout,
+		Context:                     ctx.ctx,
+		ForceSecret:                 opts.SecretInputs,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("marshaling properties: %w", err)
@@ -1074,3 +1074,126 @@ func TestGetOrRegisterPackageRef(t *testing.T) {
 		assert.Equal(t, int32(1), mon.calls.Load(), "concurrent calls should result in single registration")
 	})
 }
+
+func TestWaitForOutputs(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := NewContext(t.Context(), RunInfo{})
+	require.NoError(t, err)
+
+	out, resolve, _ := ctx.NewOutput()
+	go func() {
+		resolve("done")
+	}()
+
+	// Consume the output so its resolution work completes.
+	_, _, _, _, err = awaitWithContext(t.Context(), out)
+	require.NoError(t, err)
+
+	require.NoError(t, ctx.WaitForOutputs(t.Context()))
+
+	// A canceled wait context surfaces its error.
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+	pending, resolvePending, _ := ctx.NewOutput()
+	assert.ErrorIs(t, ctx.WaitForOutputs(canceled), context.Canceled)
+
+	// Resolve and drain the pending output so no goroutines leak out of the test.
+	resolvePending("done")
+	_, _, _, _, err = awaitWithContext(t.Context(), pending)
+	require.NoError(t, err)
+	require.NoError(t, ctx.WaitForOutputs(t.Context()))
+}
+
+func TestSecretInputsOption(t *testing.T) {
+	t.Parallel()
+
+	var inputs resource.PropertyMap
+	mocks := &testMonitor{
+		NewResourceF: func(args MockResourceArgs) (string, resource.PropertyMap, error) {
+			inputs = args.Inputs
+			return "myID", args.Inputs, nil
+		},
+	}
+
+	err := RunErr(func(ctx *Context) error {
+		var res struct{ CustomResourceState }
+		return ctx.RegisterResource("test:index:res", "test", Map{
+			"user": String("alice"),
+			"pass": ToSecret(String("hunter2")),
+		}, &res, SecretInputs())
+	}, WithMocks("project", "stack", mocks))
+	require.NoError(t, err)
+
+	require.Len(t, inputs, 2)
+	for k, v := range inputs {
+		assert.Truef(t, v.ContainsSecrets(), "property %q should be secret on the wire", k)
+	}
+}
+
+func TestCaptureRegistrations(t *testing.T) {
+	t.Parallel()
+
+	mocks := &testMonitor{
+		NewResourceF: func(args MockResourceArgs) (string, resource.PropertyMap, error) {
+			return args.Name + "-id", args.Inputs, nil
+		},
+	}
+
+	var pctx *Context
+	err := RunErr(func(ctx *Context) error {
+		pctx = ctx
+
+		var first struct{ CustomResourceState }
+		if err := ctx.RegisterResource("test:index:res", "first", Map{
+			"size": Int(3),
+		}, &first); err != nil {
+			return err
+		}
+
+		var second struct{ CustomResourceState }
+		return ctx.RegisterResource("test:index:res", "second", Map{
+			"name": String("x"),
+		}, &second)
+	}, WithMocks("project", "stack", mocks), WithCaptureRegistrations())
+	require.NoError(t, err)
+
+	// RunErr waits for all outstanding registrations, so by now both are captured. The
+	// implicit root stack registration is excluded, and the result is sorted by type and
+	// name.
+	captured := pctx.CapturedRegistrations()
+	assert.Equal(t, []CapturedRegistration{
+		{
+			Type:   "test:index:res",
+			Name:   "first",
+			Inputs: resource.PropertyMap{"size": resource.NewProperty(3.0)},
+		},
+		{
+			Type:   "test:index:res",
+			Name:   "second",
+			Inputs: resource.PropertyMap{"name": resource.NewProperty("x")},
+		},
+	}, captured)
+}
+
+func TestCaptureRegistrationsDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	mocks := &testMonitor{
+		NewResourceF: func(args MockResourceArgs) (string, resource.PropertyMap, error) {
+			return "id", args.Inputs, nil
+		},
+	}
+
+	err := RunErr(func(ctx *Context) error {
+		var res struct{ CustomResourceState }
+		if err := ctx.RegisterResource("test:index:res", "test", Map{
+			"size": Int(3),
+		}, &res); err != nil {
+			return err
+		}
+		assert.Nil(t, ctx.CapturedRegistrations())
+		return nil
+	}, WithMocks("project", "stack", mocks))
+	require.NoError(t, err)
+}
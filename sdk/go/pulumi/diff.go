@@ -0,0 +1,107 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// PropertyDiff describes the differences between two property maps as lists of property paths.
+// Nested object properties are reported as dotted paths (e.g. "parent.child") and array elements
+// with an index suffix (e.g. "tags[2]").
+type PropertyDiff struct {
+	// Added lists the paths of properties present in the new map but not the old one.
+	Added []string
+	// Removed lists the paths of properties present in the old map but not the new one.
+	Removed []string
+	// Changed lists the paths of properties present in both maps whose values differ.
+	Changed []string
+}
+
+// HasChanges returns true if the diff records any added, removed, or changed properties.
+func (d PropertyDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// DiffPropertyMaps compares two property maps and reports the paths of added, removed, and changed
+// properties. Object values are compared recursively and array values element by element. Secret
+// values are treated as opaque: a secret whose contents differ is reported as changed at the path
+// of the secret itself without descending into (and thereby revealing the shape of) its contents.
+//
+// This is intended for diagnostics and test assertions, e.g. comparing the inputs marshaled for a
+// resource against an expected property map.
+func DiffPropertyMaps(olds, news resource.PropertyMap) PropertyDiff {
+	var d PropertyDiff
+	diffPropertyMaps(olds, news, "", &d)
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+func diffPropertyMaps(olds, news resource.PropertyMap, prefix string, d *PropertyDiff) {
+	for k, old := range olds {
+		path := joinPropertyPath(prefix, string(k))
+		if new, has := news[k]; has {
+			diffPropertyValues(old, new, path, d)
+		} else {
+			d.Removed = append(d.Removed, path)
+		}
+	}
+	for k := range news {
+		if _, has := olds[k]; !has {
+			d.Added = append(d.Added, joinPropertyPath(prefix, string(k)))
+		}
+	}
+}
+
+func diffPropertyValues(old, new resource.PropertyValue, path string, d *PropertyDiff) {
+	switch {
+	case old.IsSecret() || new.IsSecret():
+		// Compare secrets opaquely so the diff does not reveal the shape of their contents.
+		if !old.DeepEquals(new) {
+			d.Changed = append(d.Changed, path)
+		}
+	case old.IsObject() && new.IsObject():
+		diffPropertyMaps(old.ObjectValue(), new.ObjectValue(), path, d)
+	case old.IsArray() && new.IsArray():
+		oldArr, newArr := old.ArrayValue(), new.ArrayValue()
+		for i := range oldArr {
+			elemPath := fmt.Sprintf("%s[%d]", path, i)
+			if i < len(newArr) {
+				diffPropertyValues(oldArr[i], newArr[i], elemPath, d)
+			} else {
+				d.Removed = append(d.Removed, elemPath)
+			}
+		}
+		for i := len(oldArr); i < len(newArr); i++ {
+			d.Added = append(d.Added, fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		if !old.DeepEquals(new) {
+			d.Changed = append(d.Changed, path)
+		}
+	}
+}
+
+func joinPropertyPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
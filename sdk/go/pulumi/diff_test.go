@@ -0,0 +1,133 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPropertyMaps(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no changes", func(t *testing.T) {
+		t.Parallel()
+
+		m := resource.PropertyMap{
+			"s": resource.NewProperty("hello"),
+			"n": resource.NewProperty(42.0),
+		}
+		d := DiffPropertyMaps(m, m)
+		assert.False(t, d.HasChanges())
+		assert.Empty(t, d.Added)
+		assert.Empty(t, d.Removed)
+		assert.Empty(t, d.Changed)
+	})
+
+	t.Run("added, removed, and changed keys", func(t *testing.T) {
+		t.Parallel()
+
+		olds := resource.PropertyMap{
+			"keep":    resource.NewProperty("same"),
+			"remove":  resource.NewProperty("old"),
+			"changed": resource.NewProperty(1.0),
+		}
+		news := resource.PropertyMap{
+			"keep":    resource.NewProperty("same"),
+			"add":     resource.NewProperty("new"),
+			"changed": resource.NewProperty(2.0),
+		}
+		d := DiffPropertyMaps(olds, news)
+		assert.True(t, d.HasChanges())
+		assert.Equal(t, []string{"add"}, d.Added)
+		assert.Equal(t, []string{"remove"}, d.Removed)
+		assert.Equal(t, []string{"changed"}, d.Changed)
+	})
+
+	t.Run("nested objects use dotted paths", func(t *testing.T) {
+		t.Parallel()
+
+		olds := resource.PropertyMap{
+			"outer": resource.NewProperty(resource.PropertyMap{
+				"inner":   resource.NewProperty("old"),
+				"removed": resource.NewProperty(true),
+			}),
+		}
+		news := resource.PropertyMap{
+			"outer": resource.NewProperty(resource.PropertyMap{
+				"inner": resource.NewProperty("new"),
+				"added": resource.NewProperty(false),
+			}),
+		}
+		d := DiffPropertyMaps(olds, news)
+		assert.Equal(t, []string{"outer.added"}, d.Added)
+		assert.Equal(t, []string{"outer.removed"}, d.Removed)
+		assert.Equal(t, []string{"outer.inner"}, d.Changed)
+	})
+
+	t.Run("arrays diff element by element", func(t *testing.T) {
+		t.Parallel()
+
+		olds := resource.PropertyMap{
+			"arr": resource.NewProperty([]resource.PropertyValue{
+				resource.NewProperty("a"),
+				resource.NewProperty("b"),
+			}),
+		}
+		news := resource.PropertyMap{
+			"arr": resource.NewProperty([]resource.PropertyValue{
+				resource.NewProperty("a"),
+				resource.NewProperty("B"),
+				resource.NewProperty("c"),
+			}),
+		}
+		d := DiffPropertyMaps(olds, news)
+		assert.Equal(t, []string{"arr[2]"}, d.Added)
+		assert.Empty(t, d.Removed)
+		assert.Equal(t, []string{"arr[1]"}, d.Changed)
+	})
+
+	t.Run("secrets are opaque", func(t *testing.T) {
+		t.Parallel()
+
+		olds := resource.PropertyMap{
+			"secret": resource.MakeSecret(resource.NewProperty(resource.PropertyMap{
+				"inner": resource.NewProperty("old"),
+			})),
+		}
+		news := resource.PropertyMap{
+			"secret": resource.MakeSecret(resource.NewProperty(resource.PropertyMap{
+				"inner": resource.NewProperty("new"),
+			})),
+		}
+		d := DiffPropertyMaps(olds, news)
+		// The secret itself is reported as changed; its contents are not descended into.
+		assert.Equal(t, []string{"secret"}, d.Changed)
+
+		// Identical secrets do not report a diff.
+		assert.False(t, DiffPropertyMaps(olds, olds).HasChanges())
+	})
+
+	t.Run("type changes are reported as changed", func(t *testing.T) {
+		t.Parallel()
+
+		olds := resource.PropertyMap{"v": resource.NewProperty("text")}
+		news := resource.PropertyMap{"v": resource.NewProperty(resource.PropertyMap{})}
+		d := DiffPropertyMaps(olds, news)
+		assert.Equal(t, []string{"v"}, d.Changed)
+	})
+}
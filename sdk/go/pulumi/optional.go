@@ -0,0 +1,41 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+// OptionalValue is implemented by sql.Null*-style optional wrappers ({Value T; Valid bool}).
+// Implementations marshal as their wrapped value when valid and as null otherwise, rather
+// than serializing as a {value, valid} object. On unmarshal, wrappers that follow the
+// Value/Valid field convention have their Value field populated and Valid set accordingly.
+type OptionalValue interface {
+	// Get returns the wrapped value and whether it is valid (present).
+	Get() (any, bool)
+}
+
+// Nullable is a generic OptionalValue adapter in the mold of the sql.Null* types. A
+// Nullable marshals as its Value when Valid is true and as null otherwise.
+type Nullable[T any] struct {
+	Value T
+	Valid bool
+}
+
+// NullableOf returns a valid Nullable wrapping the given value.
+func NullableOf[T any](value T) Nullable[T] {
+	return Nullable[T]{Value: value, Valid: true}
+}
+
+// Get returns the wrapped value and whether it is valid (present).
+func (n Nullable[T]) Get() (any, bool) {
+	return n.Value, n.Valid
+}
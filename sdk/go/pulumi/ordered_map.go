@@ -0,0 +1,57 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import "slices"
+
+// An OrderedMap is a string-keyed map that preserves the insertion order of its entries.
+// Plain Go maps iterate in random order, so their marshaled form cannot carry an ordering;
+// an OrderedMap instead marshals as an array of {key, value} pairs, which round-trips
+// through the engine with the order intact. Use it for the rare inputs whose key order is
+// meaningful, such as ordered header lists.
+//
+// The zero value is an empty map ready for use.
+type OrderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+// Set adds or replaces the entry for key. A new key is appended to the order; replacing
+// an existing key keeps its original position.
+func (m *OrderedMap) Set(key string, value any) {
+	if m.values == nil {
+		m.values = map[string]any{}
+	}
+	if _, has := m.values[key]; !has {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value stored for key and whether the key is present.
+func (m *OrderedMap) Get(key string) (any, bool) {
+	v, has := m.values[key]
+	return v, has
+}
+
+// Keys returns the map's keys in insertion order.
+func (m *OrderedMap) Keys() []string {
+	return slices.Clone(m.keys)
+}
+
+// Len returns the number of entries in the map.
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
@@ -168,6 +168,49 @@ func (s *ResourceState) keepDependency() bool {
 	return s.keepDep
 }
 
+// WalkResourceGraph traverses the resource graph rooted at root in depth-first preorder,
+// invoking visit for each reachable resource exactly once. Rehydrated reference graphs may
+// be cyclic — resource A's property can reference B while B references A — so the walk
+// keeps a visited set keyed by URN and is guaranteed to terminate. visit's return value
+// controls descent: returning false prunes the resource's children. Each resource's URN is
+// awaited before it is visited.
+func WalkResourceGraph(root Resource, visit func(Resource) bool) error {
+	visited := map[URN]struct{}{}
+	var walk func(res Resource) error
+	walk = func(res Resource) error {
+		urn, _, _, err := res.URN().awaitURN(context.Background())
+		if err != nil {
+			return err
+		}
+		if _, ok := visited[urn]; ok {
+			return nil
+		}
+		visited[urn] = struct{}{}
+		if !visit(res) {
+			return nil
+		}
+		for _, child := range res.getChildren() {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(root)
+}
+
+// NewDependencyResource constructs a Resource that carries the given URN but is not
+// registered with the engine. The returned resource can be used to establish
+// dependencies on resources managed by other stacks or programs, for example when
+// building cross-stack references by hand. The URN must be well-formed; a malformed
+// URN results in an error.
+func NewDependencyResource(ctx *Context, urn string) (Resource, error) {
+	if !resource.URN(urn).IsValid() {
+		return nil, fmt.Errorf("invalid URN %q", urn)
+	}
+	return ctx.newDependencyResource(URN(urn)), nil
+}
+
 func (ctx *Context) newDependencyResource(urn URN) Resource {
 	var res ResourceState
 	res.urn.OutputState = ctx.newOutputState(res.urn.ElementType(), &res)
@@ -188,6 +231,27 @@ func (s *CustomResourceState) ID() IDOutput {
 
 func (*CustomResourceState) isCustomResource() {}
 
+// NewDependencyCustomResource constructs a CustomResource that carries the given URN
+// and ID but is not registered with the engine. It is the custom-resource analogue of
+// NewDependencyResource: the resource's ID output resolves to the given ID, or to
+// unknown if the ID is empty. The URN must be well-formed; a malformed URN results in
+// an error.
+func NewDependencyCustomResource(ctx *Context, urn, id string) (CustomResource, error) {
+	if !resource.URN(urn).IsValid() {
+		return nil, fmt.Errorf("invalid URN %q", urn)
+	}
+	return ctx.newDependencyCustomResource(URN(urn), ID(id)), nil
+}
+
+// NewResolvedCustomResource returns a CustomResource whose URN and ID outputs are already
+// resolved to the given values. It is intended for tests that exercise resource-reference
+// marshaling: marshaling the result yields a custom resource reference immediately,
+// without awaiting any outstanding registration. Unlike NewDependencyCustomResource, the
+// URN is not validated.
+func NewResolvedCustomResource(ctx *Context, urn, id string) CustomResource {
+	return ctx.newDependencyCustomResource(URN(urn), ID(id))
+}
+
 func (ctx *Context) newDependencyCustomResource(urn URN, id ID) CustomResource {
 	var res CustomResourceState
 	res.urn.OutputState = ctx.newOutputState(res.urn.ElementType(), &res)
@@ -512,6 +576,9 @@ type ResourceOptions struct {
 	// triggers.
 	ReplacementTrigger Input
 
+	// SecretInputs marks every input property of the resource as secret on the wire.
+	SecretInputs bool
+
 	// Transformations is a list of functions that transform
 	// the resource's properties during construction.
 	Transformations []ResourceTransformation
@@ -582,6 +649,7 @@ type resourceOptions struct {
 	Providers               map[string]ProviderResource
 	ReplaceOnChanges        []string
 	ReplacementTrigger      Input
+	SecretInputs            bool
 	Transformations         []ResourceTransformation
 	Transforms              []ResourceTransform
 	URN                     string
@@ -651,6 +719,7 @@ func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
 		Providers:               providers,
 		ReplaceOnChanges:        ro.ReplaceOnChanges,
 		ReplacementTrigger:      ro.ReplacementTrigger,
+		SecretInputs:            ro.SecretInputs,
 		Transformations:         ro.Transformations,
 		Transforms:              ro.Transforms,
 		URN:                     ro.URN,
@@ -811,6 +880,18 @@ func AdditionalSecretOutputs(o []string) ResourceOption {
 	})
 }
 
+// SecretInputs marks every input property of the resource as secret on the wire, as if each
+// value had been wrapped with ToSecret. Use this when a resource's inputs are sensitive in
+// their entirety rather than wrapping each field individually. Note that this applies
+// uniformly: fields that a provider expects to receive as plain values are still delivered
+// secret, so providers that cannot handle secret inputs should not be combined with this
+// option.
+func SecretInputs() ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.SecretInputs = true
+	})
+}
+
 // Aliases applies a list of identifiers to find and use existing resources.
 func Aliases(o []Alias) ResourceOption {
 	return resourceOption(func(ro *resourceOptions) {
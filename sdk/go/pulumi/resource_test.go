@@ -1762,3 +1762,44 @@ func TestInvokeSecret(t *testing.T) {
 	}, WithMocks("project", "stack", monitor))
 	require.NoError(t, err)
 }
+
+func TestWalkResourceGraphCycle(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := NewContext(t.Context(), RunInfo{})
+	require.NoError(t, err)
+
+	const urnA = "urn:pulumi:test_stack::test_project::pkg:index:type::a"
+	const urnB = "urn:pulumi:test_stack::test_project::pkg:index:type::b"
+	a, err := NewDependencyResource(ctx, urnA)
+	require.NoError(t, err)
+	b, err := NewDependencyResource(ctx, urnB)
+	require.NoError(t, err)
+
+	// Build a two-node cycle: a -> b -> a.
+	a.(*ResourceState).addChild(b)
+	b.(*ResourceState).addChild(a)
+
+	var visited []URN
+	err = WalkResourceGraph(a, func(res Resource) bool {
+		urn, _, _, err := res.URN().awaitURN(t.Context())
+		require.NoError(t, err)
+		visited = append(visited, urn)
+		return true
+	})
+	require.NoError(t, err)
+
+	// Each resource is visited exactly once despite the cycle.
+	assert.ElementsMatch(t, []URN{urnA, urnB}, visited)
+
+	// Returning false prunes descent.
+	visited = nil
+	err = WalkResourceGraph(a, func(res Resource) bool {
+		urn, _, _, err := res.URN().awaitURN(t.Context())
+		require.NoError(t, err)
+		visited = append(visited, urn)
+		return false
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []URN{urnA}, visited)
+}
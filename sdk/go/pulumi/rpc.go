@@ -16,13 +16,20 @@ package pulumi
 
 import (
 	"context"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
+	"net/url"
 	"reflect"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/blang/semver"
 
@@ -103,16 +110,312 @@ func expandDependencies(ctx context.Context, deps []Resource) (map[URN]Resource,
 	return set, nil
 }
 
+// TransitiveDependencies computes the resources the given resource transitively depends
+// on, following the algorithm documented on addDependency: custom resources are included
+// directly, local component resources are expanded into their descendents, and remote
+// component, dependency, and rehydrated component resources are included as aggregation
+// nodes without descending into their children. Dependency-graph and visualization tools
+// can use this to reuse the SDK's canonical expansion rather than reimplementing it. The
+// result is sorted by URN.
+func TransitiveDependencies(ctx context.Context, r Resource) ([]Resource, error) {
+	set := map[URN]Resource{}
+	var visit func(res Resource) error
+	visit = func(res Resource) error {
+		add := func() error {
+			urn, _, _, err := res.URN().awaitURN(ctx)
+			if err != nil {
+				return err
+			}
+			set[urn] = res
+			return nil
+		}
+		// Custom resources are real graph nodes: include them directly without
+		// descending into their children.
+		if _, custom := res.(CustomResource); custom {
+			return add()
+		}
+		// Remote component, dependency, and rehydrated component resources act as
+		// aggregation nodes: include them without descending into their children.
+		if res.keepDependency() {
+			return add()
+		}
+		// Local component resources expand into their descendents.
+		for _, child := range res.getChildren() {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := visit(r); err != nil {
+		return nil, err
+	}
+	urns := slices.Sorted(maps.Keys(set))
+	resources := slice.Prealloc[Resource](len(urns))
+	for _, urn := range urns {
+		resources = append(resources, set[urn])
+	}
+	return resources, nil
+}
+
+// DependenciesOf computes the URNs of the resources the given input depends on. Unlike
+// marshaling, it never builds a PropertyValue tree: it walks the input collecting
+// resources and output dependencies, awaiting only resource URNs. Component resources are
+// expanded into their children via expandDependencies, mirroring the dependency
+// computation performed during input marshaling. The returned URNs are sorted.
+func DependenciesOf(ctx *Context, v Input) ([]URN, error) {
+	var resources []Resource
+	var collect func(v any) error
+	collect = func(v any) error {
+		if v == nil {
+			return nil
+		}
+		switch v := v.(type) {
+		case Resource:
+			resources = append(resources, v)
+			return nil
+		case Output:
+			val, _, _, deps, err := awaitWithContext(ctx.Context(), v)
+			if err != nil {
+				return err
+			}
+			resources = append(resources, deps...)
+			return collect(val)
+		}
+		rv := reflect.ValueOf(v)
+		//nolint:exhaustive // Scalars cannot carry dependencies.
+		switch rv.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if rv.IsNil() {
+				return nil
+			}
+			return collect(rv.Elem().Interface())
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				if err := collect(rv.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+		case reflect.Map:
+			for _, key := range rv.MapKeys() {
+				if err := collect(rv.MapIndex(key).Interface()); err != nil {
+					return err
+				}
+			}
+		case reflect.Struct:
+			typ := rv.Type()
+			for i := 0; i < typ.NumField(); i++ {
+				if f := typ.Field(i); !f.IsExported() || (f.Anonymous && isResourceStateType(f.Type)) {
+					continue
+				}
+				if err := collect(rv.Field(i).Interface()); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := collect(v); err != nil {
+		return nil, err
+	}
+
+	set, err := expandDependencies(ctx.Context(), resources)
+	if err != nil {
+		return nil, err
+	}
+	urns := slice.Prealloc[URN](len(set))
+	for urn := range set {
+		urns = append(urns, urn)
+	}
+	slices.Sort(urns)
+	return urns, nil
+}
+
 // marshalOptions controls the options for marshaling inputs.
 type marshalOptions struct {
 	// Set to true to error if any Outputs are present; otherwise Outputs will be awaited.
 	ErrorOnOutput bool
 
+	// Set to true to error if any awaited output reports itself as secret, naming the
+	// offending property. This enforces "this field may not be a secret" invariants for
+	// values that are always rendered in the clear (e.g. a region name).
+	ErrorOnSecret bool
+
 	// Set to true to exclude resource references from the set of dependencies identified
 	// during marshaling. This is useful for remote components (i.e. MLCs) where we want
 	// propertyDependencies to be empty for a property that only contains resource
 	// references.
 	ExcludeResourceRefsFromDeps bool
+
+	// Set to true to exclude provider resources from the set of dependencies identified
+	// during marshaling. A ProviderResource passed as a property value is still marshaled
+	// as a resource reference -- the engine tracks the provider relationship through the
+	// reference itself -- but it is not recorded as a data dependency, which would
+	// otherwise impose spurious ordering between the provider and consumers of the value.
+	// Non-provider resources are unaffected.
+	ExcludeProviderRefsFromDeps bool
+
+	// Set to true to marshal any Output as an unknown computed value without awaiting it.
+	// This allows static tooling to inspect the structural shape of a property map for a
+	// partially-constructed program without blocking on unresolvable outputs.
+	TreatOutputsAsUnknown bool
+
+	// The maximum recursion depth permitted when marshaling a value. Zero applies the
+	// default (defaultMaxMarshalDepth). This bounds self-referential values, which would
+	// otherwise recurse until stack overflow.
+	MaxDepth int
+
+	// The maximum time to wait for any single output or URN to resolve during marshaling.
+	// Zero waits indefinitely. See Context.WithOutputTimeout.
+	OutputTimeout time.Duration
+
+	// The context governing awaits performed during marshaling. Cancelling it interrupts
+	// values whose outputs, URNs, or IDs are still pending rather than hanging. Nil
+	// defaults to context.Background().
+	Context context.Context
+
+	// Set to true to fall back to a struct field's `json` tag when it has no `pulumi` tag,
+	// honoring "-" and "omitempty". This lets existing typed API client structs be used
+	// directly as inputs.
+	UseJSONTagsFallback bool
+
+	// Set to true to emit a computed placeholder for each property whose value is absent
+	// (null with no dependencies) instead of omitting it from the property map. Preview
+	// tooling uses this to render every field of the resource shape, including unset
+	// optional ones.
+	IncludeAbsentAsComputed bool
+
+	// DependencyFilter, when set, limits the dependencies recorded while marshaling inputs
+	// to the URNs for which it returns true. Resource references are still emitted into
+	// the property values; only the dependency accounting (the per-property and aggregate
+	// dependency sets) is filtered. This supports sandboxed execution modes that must not
+	// depend on resources owned elsewhere (e.g. by another stack).
+	DependencyFilter func(URN) bool
+
+	// OnProperty, when set, is invoked for each property marshalInputs emits into the
+	// resulting property map, with the property's key and its fully-resolved value.
+	// Properties that are dropped (null values without dependencies) are not reported.
+	// Secretness can be inspected on the value itself (e.g. ContainsSecrets). This enables
+	// in-process auditing of the marshaled inputs without re-parsing the protobuf later.
+	OnProperty func(key string, v resource.PropertyValue)
+
+	// MaxDependenciesPerProperty, when positive, bounds the number of dependencies a single
+	// property may carry; exceeding it fails the marshal with an error naming the property.
+	// A safety valve for untrusted or buggy programs that would otherwise balloon the
+	// registration RPC (e.g. by depending on every resource in a huge stack). Zero means
+	// unlimited.
+	MaxDependenciesPerProperty int
+
+	// OnUnknown, when set, is invoked with the key of each property marshalInputs emits
+	// whose value is or contains an unknown (computed) value. Preview tooling uses this to
+	// render "(known after apply)" precisely without re-deriving the unknowns from the
+	// marshaled protobuf.
+	OnUnknown func(key string)
+
+	// Set to true to reject interface-element slices (e.g. []any) whose marshaled elements
+	// are not all of the same property-value type. Typed slices are homogeneous by
+	// construction; some providers reject heterogeneous arrays, and this surfaces the
+	// mismatch at marshal time with the property path rather than in a provider error.
+	RequireHomogeneousArrays bool
+
+	// Set to true to marshal without spawning background goroutines: outputs, URNs, and IDs
+	// are awaited inline on the calling goroutine and no timeout timers are started
+	// (OutputTimeout is ignored). Already-resolved outputs are read synchronously. This keeps
+	// marshaling deterministic and leak-free for tests that assert on goroutine counts.
+	Synchronous bool
+
+	// Set to true to marshal maps with integer keys (e.g. a map[int]string of port mappings)
+	// by stringifying each key in base 10 rather than rejecting the map. Maps keyed by other
+	// non-string types still error. Unmarshaling into an integer-keyed destination map parses
+	// the keys back.
+	StringifyNonStringMapKeys bool
+
+	// Set to true to marshal struct types that have no `pulumi`-tagged fields but implement
+	// fmt.Stringer via their String() method rather than as an empty object. This helps when
+	// passing third-party config types that only expose a string form. Opt-in so that types
+	// that legitimately want struct serialization are unaffected.
+	UseStringerForUnknownStructs bool
+
+	// Set to true to mark every marshaled property as secret on the wire, regardless of
+	// whether its value carries secretness of its own. Derived from the SecretInputs
+	// resource option during registration.
+	ForceSecret bool
+}
+
+// propertyTagName resolves the wire name of a struct field: the `pulumi` tag if present,
+// else (when useJSONFallback is set) the `json` tag, skipping fields tagged "-" and
+// defaulting to the field name if the json tag has options but no name. The second result
+// reports the json tag's omitempty flag; the third reports whether the field participates
+// in marshaling at all.
+func propertyTagName(f reflect.StructField, useJSONFallback bool) (string, bool, bool) {
+	if tag := strings.Split(f.Tag.Get("pulumi"), ",")[0]; tag != "" {
+		return tag, false, true
+	}
+	if !useJSONFallback {
+		return "", false, false
+	}
+	jsonTag, ok := f.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+	parts := strings.Split(jsonTag, ",")
+	name := parts[0]
+	if name == "-" {
+		return "", false, false
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, slices.Contains(parts[1:], "omitempty"), true
+}
+
+// awaitContext returns the context used to await outputs and URNs during marshaling,
+// bounded by OutputTimeout if one is set.
+func (opts *marshalOptions) awaitContext() (context.Context, context.CancelFunc) {
+	base := context.Background()
+	if opts != nil && opts.Context != nil {
+		base = opts.Context
+	}
+	// Synchronous marshaling never starts a timeout timer: the await happens inline on the
+	// calling goroutine.
+	if opts == nil || opts.OutputTimeout <= 0 || opts.Synchronous {
+		return base, func() {}
+	}
+	return context.WithTimeout(base, opts.OutputTimeout)
+}
+
+// awaitErrorf wraps an await failure caused by the await context expiring, distinguishing
+// cancellation of the marshaling context from an elapsed OutputTimeout. what describes the
+// value being awaited (e.g. "output", "URN of resource") and path locates it.
+func (opts *marshalOptions) awaitErrorf(ctx context.Context, what, path string) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return fmt.Errorf("awaiting %s at %s: %w", what, path, context.Canceled)
+	}
+	return fmt.Errorf("awaiting %s at %s: timed out after %v", what, path, opts.OutputTimeout)
+}
+
+// genericOptionValue reports whether v follows the generic option convention of a
+// `Value() (T, bool)` method, which cannot be expressed as a non-generic interface since
+// T erases at reflect time. It returns the inner value and whether it is present.
+func genericOptionValue(v any) (inner any, valid, ok bool) {
+	m := reflect.ValueOf(v).MethodByName("Value")
+	if !m.IsValid() {
+		return nil, false, false
+	}
+	mt := m.Type()
+	if mt.NumIn() != 0 || mt.NumOut() != 2 || mt.Out(1) != boolType {
+		return nil, false, false
+	}
+	out := m.Call(nil)
+	return out[0].Interface(), out[1].Bool(), true
+}
+
+// PropertyMapper is implemented by custom map types (ordered maps, sync.Map wrappers,
+// etc.) that want to marshal as a Pulumi input map. The marshaler consults this interface
+// before its generic map handling, so such types need not be converted to a plain Go map
+// first.
+type PropertyMapper interface {
+	ToPropertyMap() map[string]any
 }
 
 // marshalInputs turns resource property inputs into a map suitable for marshaling.
@@ -130,8 +433,10 @@ func marshalInputsOptions(props Input, opts *marshalOptions) (resource.PropertyM
 	}
 
 	marshalProperty := func(pname string, pv any, pt reflect.Type) error {
-		// Get the underlying value, possibly waiting for an output to arrive.
-		v, resourceDeps, err := marshalInputOptions(pv, pt, opts)
+		// Get the underlying value, possibly waiting for an output to arrive. The property
+		// name seeds the path so that timeout errors and await trace events name the
+		// property being marshaled.
+		v, resourceDeps, err := marshalInputAtDepth(pv, pt, opts, false /*skipInputCheck*/, pname, 0)
 		if err != nil {
 			return fmt.Errorf("awaiting input property %q: %w", pname, err)
 		}
@@ -141,10 +446,27 @@ func marshalInputsOptions(props Input, opts *marshalOptions) (resource.PropertyM
 		if err != nil {
 			return err
 		}
+		if opts != nil && opts.DependencyFilter != nil {
+			for urn := range allDeps {
+				if !opts.DependencyFilter(urn) {
+					delete(allDeps, urn)
+				}
+			}
+		}
+		if opts != nil && opts.MaxDependenciesPerProperty > 0 && len(allDeps) > opts.MaxDependenciesPerProperty {
+			return fmt.Errorf("property %q has %d dependencies, exceeding the limit of %d",
+				pname, len(allDeps), opts.MaxDependenciesPerProperty)
+		}
 		for k := range allDeps {
 			deps[k] = struct{}{}
 		}
 
+		// Blanket-secret registration: wrap every present value, leaving values that are
+		// already secret untouched.
+		if opts != nil && opts.ForceSecret && !v.IsNull() && !v.IsSecret() {
+			v = resource.MakeSecret(v)
+		}
+
 		if !v.IsNull() || len(allDeps) > 0 {
 			urns := slice.Prealloc[URN](len(allDeps))
 			for v := range allDeps {
@@ -152,14 +474,46 @@ func marshalInputsOptions(props Input, opts *marshalOptions) (resource.PropertyM
 			}
 			pmap[resource.PropertyKey(pname)] = v
 			pdeps[pname] = urns
+			if opts != nil && opts.OnProperty != nil {
+				opts.OnProperty(pname, v)
+			}
+			if opts != nil && opts.OnUnknown != nil && v.ContainsUnknowns() {
+				opts.OnUnknown(pname)
+			}
+		} else if opts != nil && opts.IncludeAbsentAsComputed {
+			// Emit a computed placeholder for the absent property so previews render the
+			// full resource shape.
+			v = resource.MakeComputed(resource.NewProperty(""))
+			pmap[resource.PropertyKey(pname)] = v
+			if opts.OnProperty != nil {
+				opts.OnProperty(pname, v)
+			}
+			if opts.OnUnknown != nil {
+				opts.OnUnknown(pname)
+			}
 		}
 		return nil
 	}
 
+	if err := eachInputProperty(props, marshalProperty); err != nil {
+		return nil, nil, nil, err
+	}
+
+	urns := slice.Prealloc[URN](len(deps))
+	for v := range deps {
+		urns = append(urns, v)
+	}
+	return pmap, pdeps, urns, nil
+}
+
+// eachInputProperty walks the properties of props (a struct- or map-backed input),
+// invoking f with each property's name, value, and destination type. Struct-backed inputs
+// are walked in field declaration order; map-backed inputs follow map iteration order.
+func eachInputProperty(props Input, f func(pname string, pv any, pt reflect.Type) error) error {
 	pv := reflect.ValueOf(props)
 	if pv.Kind() == reflect.Pointer {
 		if pv.IsNil() {
-			return pmap, pdeps, nil, nil
+			return nil
 		}
 		pv = pv.Elem()
 	}
@@ -180,18 +534,60 @@ func marshalInputsOptions(props Input, opts *marshalOptions) (resource.PropertyM
 			rt = rt.Elem()
 		}
 		getMappedField := internal.MapStructTypes(pt, rt)
-		// Now, marshal each field in the input.
+		// Now, marshal each field in the input. `pulumi:",inline"` catch-all fields are
+		// collected and emitted after the declared fields so explicit properties win on
+		// key collision.
+		var inlineFields []reflect.StructField
+		var inlineValues []reflect.Value
+		declared := map[string]struct{}{}
 		numFields := pt.NumField()
 		for i := range numFields {
 			destField, _ := getMappedField(reflect.Value{}, i)
+			if hasTagFlag(destField.Tag, "inline") {
+				if destField.Type.Kind() != reflect.Map || destField.Type.Key().Kind() != reflect.String {
+					return fmt.Errorf("inline field %s must be a map with string keys", destField.Name)
+				}
+				inlineFields = append(inlineFields, destField)
+				inlineValues = append(inlineValues, pv.Field(i))
+				continue
+			}
 			tag := destField.Tag.Get("pulumi")
 			tag = strings.Split(tag, ",")[0] // tagName,flag => tagName
 			if tag == "" {
 				continue
 			}
-			err := marshalProperty(tag, pv.Field(i).Interface(), destField.Type)
-			if err != nil {
-				return nil, nil, nil, err
+			declared[tag] = struct{}{}
+			val := pv.Field(i).Interface()
+			if name, ok := tagOption(destField.Tag, "serializer"); ok {
+				sv, err := callFieldSerializer(pv, name, destField.Name)
+				if err != nil {
+					return err
+				}
+				val = preSerialized{sv}
+			}
+			if d, ok := val.(time.Duration); ok && hasTagFlag(destField.Tag, "durationnanos") {
+				val = durationNanos(d)
+			}
+			if b, ok := val.([]byte); ok && hasTagFlag(destField.Tag, "bytearray") {
+				val = numericBytes(b)
+			}
+			if hasTagFlag(destField.Tag, "secret") {
+				val = secretFlagged{val}
+			}
+			if err := f(tag, val, destField.Type); err != nil {
+				return err
+			}
+		}
+		for n, fv := range inlineValues {
+			elem := inlineFields[n].Type.Elem()
+			for _, key := range fv.MapKeys() {
+				k := key.String()
+				if _, exists := declared[k]; exists {
+					continue
+				}
+				if err := f(k, fv.MapIndex(key).Interface(), elem); err != nil {
+					return err
+				}
 			}
 		}
 	case reflect.Map:
@@ -201,20 +597,146 @@ func marshalInputsOptions(props Input, opts *marshalOptions) (resource.PropertyM
 		for _, key := range pv.MapKeys() {
 			keyname := key.Interface().(string)
 			val := pv.MapIndex(key).Interface()
-			err := marshalProperty(keyname, val, rt.Elem())
-			if err != nil {
-				return nil, nil, nil, err
+			if err := f(keyname, val, rt.Elem()); err != nil {
+				return err
 			}
 		}
 	default:
-		return nil, nil, nil, fmt.Errorf("cannot marshal Input that is not a struct or map, saw type %s", pt.String())
+		return fmt.Errorf("cannot marshal Input that is not a struct or map, saw type %s", pt.String())
 	}
+	return nil
+}
 
-	urns := slice.Prealloc[URN](len(deps))
-	for v := range deps {
-		urns = append(urns, v)
+// MarshalInputsStream marshals the properties of props one at a time, invoking emit for
+// each property as soon as it resolves rather than accumulating the entire property map in
+// memory. Null properties are skipped, matching the batch marshaler. Struct-backed inputs
+// emit in field declaration order; map-backed inputs emit in map iteration order. An error
+// from emit aborts the walk.
+func MarshalInputsStream(props Input, emit func(key string, v resource.PropertyValue) error) error {
+	if props == nil {
+		return nil
+	}
+	return eachInputProperty(props, func(pname string, pv any, pt reflect.Type) error {
+		v, _, err := marshalInput(pv, pt)
+		if err != nil {
+			return fmt.Errorf("awaiting input property %q: %w", pname, err)
+		}
+		if v.IsNull() {
+			return nil
+		}
+		return emit(pname, v)
+	})
+}
+
+// InputToJSON marshals the given property inputs and returns the JSON representation of the
+// resulting property map, resolving any outputs along the way. Secret values are masked as
+// "[secret]" and unknown values are rendered as "[unknown]". This is a debugging convenience
+// for inspecting the shape of the properties the engine would receive.
+func InputToJSON(ctx *Context, props Input) (string, error) {
+	pmap, _, _, err := marshalInputs(props)
+	if err != nil {
+		return "", err
+	}
+
+	m := make(map[string]any, len(pmap))
+	for k, v := range pmap {
+		m[string(k)] = inputJSONValue(v)
+	}
+	b, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// inputJSONValue converts a marshaled property value into a JSON-encodable value, masking
+// secrets and tagging unknowns.
+func inputJSONValue(v resource.PropertyValue) any {
+	switch {
+	case v.IsComputed():
+		return "[unknown]"
+	case v.IsSecret():
+		return "[secret]"
+	case v.IsOutput():
+		ov := v.OutputValue()
+		switch {
+		case ov.Secret:
+			return "[secret]"
+		case !ov.Known:
+			return "[unknown]"
+		}
+		return inputJSONValue(ov.Element)
+	case v.IsArray():
+		arr := make([]any, len(v.ArrayValue()))
+		for i, e := range v.ArrayValue() {
+			arr[i] = inputJSONValue(e)
+		}
+		return arr
+	case v.IsObject():
+		obj := make(map[string]any, len(v.ObjectValue()))
+		for k, e := range v.ObjectValue() {
+			obj[string(k)] = inputJSONValue(e)
+		}
+		return obj
+	case v.IsResourceReference():
+		return string(v.ResourceReferenceValue().URN)
+	default:
+		return v.Mappable()
+	}
+}
+
+var (
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	contextType       = reflect.TypeFor[context.Context]()
+	regexpType        = reflect.TypeFor[regexp.Regexp]()
+	urlType           = reflect.TypeFor[url.URL]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	propertyValueType = reflect.TypeFor[resource.PropertyValue]()
+	orderedMapType    = reflect.TypeFor[OrderedMap]()
+	byteType          = reflect.TypeFor[byte]()
+	errorType         = reflect.TypeFor[error]()
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+)
+
+// rawJSONValue converts a property value into a JSON-encodable value for json.RawMessage
+// destinations, unwrapping secrets and outputs. The boolean result reports whether the value
+// or any nested value was secret.
+func rawJSONValue(v resource.PropertyValue) (any, bool) {
+	switch {
+	case v.IsSecret():
+		inner, _ := rawJSONValue(v.SecretValue().Element)
+		return inner, true
+	case v.IsOutput():
+		ov := v.OutputValue()
+		if !ov.Known {
+			return nil, ov.Secret
+		}
+		inner, secret := rawJSONValue(ov.Element)
+		return inner, secret || ov.Secret
+	case v.IsComputed():
+		return nil, false
+	case v.IsArray():
+		arr := make([]any, len(v.ArrayValue()))
+		secret := false
+		for i, e := range v.ArrayValue() {
+			ev, esecret := rawJSONValue(e)
+			arr[i], secret = ev, secret || esecret
+		}
+		return arr, secret
+	case v.IsObject():
+		obj := make(map[string]any, len(v.ObjectValue()))
+		secret := false
+		for k, e := range v.ObjectValue() {
+			ev, esecret := rawJSONValue(e)
+			obj[string(k)], secret = ev, secret || esecret
+		}
+		return obj, secret
+	case v.IsResourceReference():
+		return string(v.ResourceReferenceValue().URN), false
+	default:
+		return v.Mappable(), false
 	}
-	return pmap, pdeps, urns, nil
 }
 
 // `gosec` thinks these are credentials, but they are not.
@@ -236,14 +758,139 @@ func marshalInputOptions(
 	return marshalInputOptionsImpl(v, destType, opts, false /*skipInputCheck*/)
 }
 
+// AwaitEvent describes a single await performed by the input marshaler.
+type AwaitEvent struct {
+	// Kind identifies what is being awaited: "output", "URN", or "ID".
+	Kind string
+	// Property is the property path whose marshaling triggered the await.
+	Property string
+	// Done is false when the await begins and true when it completes.
+	Done bool
+}
+
+// OutputAwaitTracer, when non-nil, is invoked before and after the input marshaler awaits
+// each output, resource URN, and resource ID. It is a debugging hook for deployments that
+// hang during marshaling: recording begin events without a matching end event pinpoints the
+// output that never resolved. The tracer must be installed before marshaling begins and must
+// be safe for concurrent use. There is no cost when the tracer is nil.
+var OutputAwaitTracer func(event AwaitEvent)
+
+// traceAwait emits a begin event for an await and returns a function that emits the matching
+// end event. Both are no-ops when no tracer is installed.
+func traceAwait(kind, property string) func() {
+	tracer := OutputAwaitTracer
+	if tracer == nil {
+		return func() {}
+	}
+	tracer(AwaitEvent{Kind: kind, Property: property})
+	return func() { tracer(AwaitEvent{Kind: kind, Property: property, Done: true}) }
+}
+
+// defaultMaxMarshalDepth bounds recursion in marshalInputAtDepth so that self-referential
+// values produce an error rather than a stack overflow. The default is generous enough for
+// any legitimate property structure and may be adjusted via marshalOptions.MaxDepth.
+const defaultMaxMarshalDepth = 10000
+
 // marshalInputImpl marshals an input value, returning its raw serializable value along with any dependencies.
 func marshalInputOptionsImpl(v any,
 	destType reflect.Type,
 	opts *marshalOptions,
 	skipInputCheck bool,
 ) (resource.PropertyValue, []Resource, error) {
+	return marshalInputAtDepth(v, destType, opts, skipInputCheck, "", 0)
+}
+
+// marshalInputAtDepth marshals an input value, tracking the property path and recursion
+// depth so that overly-deep (e.g. self-referential) values fail cleanly.
+// materializeIterator detects Go range-over-func iterators by their function signatures and
+// drains them into concrete collections: an iter.Seq[T] becomes a []any and an iter.Seq2[K, V]
+// with string keys becomes a map[string]any. Detection is structural -- a func value taking a
+// single yield func that returns bool -- since generic instantiations erase the iter named types.
+// The second return value reports whether v was an iterator.
+func materializeIterator(v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Func || rv.IsNil() {
+		return nil, false
+	}
+	seq := rv.Type()
+	if seq.NumIn() != 1 || seq.NumOut() != 0 {
+		return nil, false
+	}
+	yield := seq.In(0)
+	if yield.Kind() != reflect.Func || yield.NumOut() != 1 || yield.Out(0).Kind() != reflect.Bool {
+		return nil, false
+	}
+
+	switch yield.NumIn() {
+	case 1:
+		// iter.Seq[T]
+		elems := []any{}
+		rv.Call([]reflect.Value{reflect.MakeFunc(yield, func(args []reflect.Value) []reflect.Value {
+			elems = append(elems, args[0].Interface())
+			return []reflect.Value{reflect.ValueOf(true)}
+		})})
+		return elems, true
+	case 2:
+		// iter.Seq2[K, V] with string keys; other key types are not representable as properties.
+		if yield.In(0).Kind() != reflect.String {
+			return nil, false
+		}
+		obj := map[string]any{}
+		rv.Call([]reflect.Value{reflect.MakeFunc(yield, func(args []reflect.Value) []reflect.Value {
+			obj[args[0].String()] = args[1].Interface()
+			return []reflect.Value{reflect.ValueOf(true)}
+		})})
+		return obj, true
+	}
+	return nil, false
+}
+
+func marshalInputAtDepth(v any,
+	destType reflect.Type,
+	opts *marshalOptions,
+	skipInputCheck bool,
+	path string,
+	depth int,
+) (resource.PropertyValue, []Resource, error) {
+	maxDepth := defaultMaxMarshalDepth
+	if opts != nil && opts.MaxDepth > 0 {
+		maxDepth = opts.MaxDepth
+	}
+	if depth > maxDepth {
+		if path == "" {
+			path = "."
+		}
+		return resource.PropertyValue{}, nil, fmt.Errorf("marshaling exceeded maximum depth at %s", path)
+	}
+
 	var deps []Resource
 	for {
+		// If this is a lazy input -- a function that constructs its input on demand -- invoke it now and
+		// marshal the result. This defers expensive input construction until the value is actually
+		// serialized.
+		switch fn := v.(type) {
+		case func() Input:
+			v, destType = fn(), anyType
+			continue
+		case func(context.Context) (Input, error):
+			awaitCtx, cancel := opts.awaitContext()
+			input, err := fn(awaitCtx)
+			cancel()
+			if err != nil {
+				return resource.PropertyValue{}, nil, err
+			}
+			v, destType = input, anyType
+			continue
+		}
+
+		// Materialize Go range-over-func iterators (iter.Seq[T], iter.Seq2[string, V]) into
+		// concrete collections so lazily-generated sequences can be passed as list and map
+		// inputs without collecting them first.
+		if collected, ok := materializeIterator(v); ok {
+			v, destType = collected, anyType
+			continue
+		}
+
 		valueType := reflect.TypeOf(v)
 
 		// If this is an Input, make sure it is of the proper type and await it if it is an output/
@@ -261,12 +908,25 @@ func marshalInputOptionsImpl(v any,
 				destType = destType.Elem()
 			}
 
+			// If the destination is the input's own concrete type (e.g. a String struct
+			// field holding a String), marshal the input as its element value directly.
+			if valueType != destType && reflect.TypeOf(input) == destType {
+				destType = valueType
+			}
+
 			// If the element type of the input is not identical to the type of the destination and the destination is
 			// not the any type, attempt to convert the input to an appropriately-typed output.
 			if valueType != destType && destType != anyType {
 				if newOutput, ok := internal.CallToOutputMethod(context.TODO(), reflect.ValueOf(input), destType); ok {
 					// We were able to convert the input. Use the result as the new input value.
 					input, valueType = newOutput, destType
+				} else if destType.Kind() == reflect.Interface && reflect.TypeOf(input).AssignableTo(destType) &&
+					(opts == nil || !opts.ErrorOnOutput) {
+					// The destination is an Input interface that the value already satisfies,
+					// e.g. the element type of a map[string]StringInput. Marshal the input as
+					// its element value. Plain marshaling (ErrorOnOutput) keeps the type
+					// mismatch below so Input-typed args surface the documented error.
+					destType = valueType
 				} else if !valueType.AssignableTo(destType) {
 					err := fmt.Errorf(
 						"cannot marshal an input of type %T with element type %v as a value of type %v",
@@ -281,25 +941,52 @@ func marshalInputOptionsImpl(v any,
 					return resource.PropertyValue{}, nil, fmt.Errorf(cannotAwaitFmt, output)
 				}
 
+				// When treating outputs as unknown, emit a computed placeholder immediately
+				// rather than awaiting the output or collecting its dependencies.
+				if opts != nil && opts.TreatOutputsAsUnknown {
+					return resource.MakeComputed(resource.NewProperty("")), nil, nil
+				}
+
 				// Await the output.
-				ov, known, secret, outputDeps, err := awaitWithContext(context.TODO(), output)
+				awaitCtx, cancel := opts.awaitContext()
+				done := traceAwait("output", path)
+				ov, known, secret, outputDeps, err := awaitWithContext(awaitCtx, output)
+				done()
+				cancel()
 				if err != nil {
+					if awaitCtx.Err() != nil {
+						return resource.PropertyValue{}, nil, opts.awaitErrorf(awaitCtx, "output", path)
+					}
 					return resource.PropertyValue{}, nil, err
 				}
 
-				// Get the underlying value, if known.
+				if secret && opts != nil && opts.ErrorOnSecret {
+					if path == "" {
+						path = "."
+					}
+					return resource.PropertyValue{}, nil,
+						fmt.Errorf("unexpected secret value at %s: the property may not be a secret", path)
+				}
+
+				// Get the underlying value, if known. The resolved value may itself carry
+				// dependencies--e.g. an output that resolves to a resource--so keep the
+				// dependencies reported by the recursive marshal as well.
 				var element resource.PropertyValue
+				var elementDeps []Resource
 				if known {
-					element, _, err = marshalInputOptionsImpl(ov, destType, opts, true /*skipInputCheck*/)
+					element, elementDeps, err = marshalInputAtDepth(ov, destType, opts, true /*skipInputCheck*/, path, depth+1)
 					if err != nil {
 						return resource.PropertyValue{}, nil, err
 					}
 
 					// If it's known, not a secret, and has no deps, return the value itself.
 					if !secret && len(outputDeps) == 0 {
-						return element, nil, nil
+						return element, elementDeps, nil
 					}
 				}
+				if len(elementDeps) > 0 {
+					outputDeps = append(outputDeps[:len(outputDeps):len(outputDeps)], elementDeps...)
+				}
 
 				// Expand dependencies.
 				depSet, err := expandDependencies(context.TODO(), outputDeps)
@@ -341,8 +1028,89 @@ func marshalInputOptionsImpl(v any,
 			return resource.PropertyValue{}, nil, nil
 		}
 
+		// If the destination expects an Archive but the value is a slice or map of assets,
+		// bridge the gap by constructing an asset archive on the fly. Slice elements are
+		// keyed by their index.
+		if destType == archiveType {
+			switch assets := v.(type) {
+			case []Asset:
+				m := make(map[string]any, len(assets))
+				for i, a := range assets {
+					m[strconv.Itoa(i)] = a
+				}
+				v = NewAssetArchive(m)
+			case map[string]Asset:
+				m := make(map[string]any, len(assets))
+				for k, a := range assets {
+					m[k] = a
+				}
+				v = NewAssetArchive(m)
+			}
+		}
+
 		// Look for some well known types.
 		switch v := v.(type) {
+		case time.Duration:
+			// Durations marshal as their Go duration string (e.g. "1h30m") by default;
+			// fields tagged with the "durationnanos" flag keep the numeric form.
+			return resource.NewProperty(v.String()), deps, nil
+		case durationNanos:
+			return resource.NewProperty(float64(v)), deps, nil
+		case numericBytes:
+			// Fields tagged with the "bytearray" flag keep the legacy number-array form
+			// instead of the base64 string default.
+			arr := make([]resource.PropertyValue, len(v))
+			for i, b := range v {
+				arr[i] = resource.NewProperty(float64(b))
+			}
+			return resource.NewProperty(arr), deps, nil
+		case preSerialized:
+			// Fields tagged with a "serializer=Method" option have already been converted
+			// by the named method; emit its result verbatim.
+			return v.value, deps, nil
+		case secretFlagged:
+			// Fields tagged `pulumi:"name,secret"` always emit secrets, whether or not the
+			// source value was wrapped with ToSecret.
+			fv, d, err := marshalInputAtDepth(v.value, destType, opts, false, path, depth+1)
+			if err != nil {
+				return resource.PropertyValue{}, nil, err
+			}
+			deps = append(deps, d...)
+			if !fv.IsNull() && !fv.IsSecret() {
+				fv = resource.MakeSecret(fv)
+			}
+			return fv, deps, nil
+		case *regexp.Regexp:
+			// Compiled regular expressions marshal as their pattern string rather than by
+			// reflecting over the regexp internals.
+			if v == nil {
+				return resource.PropertyValue{}, deps, nil
+			}
+			return resource.NewProperty(v.String()), deps, nil
+		case *url.URL:
+			// URLs marshal as their string form rather than as an object of the URL's
+			// fields, which providers do not expect.
+			if v == nil {
+				return resource.PropertyValue{}, deps, nil
+			}
+			return resource.NewProperty(v.String()), deps, nil
+		case OptionalValue:
+			// sql.Null*-style optional wrappers marshal as their wrapped value or null
+			// based on validity rather than as a {value, valid} object.
+			inner, valid := v.Get()
+			if !valid {
+				return resource.PropertyValue{}, deps, nil
+			}
+			return marshalInputAtDepth(inner, anyType, opts, false, path, depth+1)
+		case *OrderedMap:
+			// Ordered maps marshal as an array of {key, value} pairs so entry order
+			// survives the trip through the engine's unordered property maps.
+			if v == nil {
+				return resource.PropertyValue{}, deps, nil
+			}
+			return marshalOrderedMap(v, opts, path, depth, deps)
+		case OrderedMap:
+			return marshalOrderedMap(&v, opts, path, depth, deps)
 		case *asset:
 			if v.invalid {
 				return resource.PropertyValue{}, nil, errors.New("invalid asset")
@@ -361,7 +1129,7 @@ func marshalInputOptionsImpl(v any,
 			if as := v.Assets(); as != nil {
 				assets = make(map[string]any)
 				for k, a := range as {
-					aa, _, err := marshalInputOptions(a, anyType, opts)
+					aa, _, err := marshalInputAtDepth(a, anyType, opts, false, path+"."+k, depth+1)
 					if err != nil {
 						return resource.PropertyValue{}, nil, err
 					}
@@ -374,28 +1142,85 @@ func marshalInputOptionsImpl(v any,
 				URI:    v.URI(),
 			}), deps, nil
 		case Resource:
-			if opts == nil || !opts.ExcludeResourceRefsFromDeps {
+			_, isProvider := v.(ProviderResource)
+			keep := opts == nil || !opts.ExcludeResourceRefsFromDeps
+			if isProvider && opts != nil && opts.ExcludeProviderRefsFromDeps {
+				keep = false
+			}
+			if keep {
 				deps = append(deps, v)
 			}
 
-			urn, known, secretURN, err := v.URN().awaitURN(context.Background())
+			awaitCtx, cancel := opts.awaitContext()
+			done := traceAwait("URN", path)
+			urn, known, secretURN, err := v.URN().awaitURN(awaitCtx)
+			done()
+			cancel()
 			if err != nil {
+				if awaitCtx.Err() != nil {
+					return resource.PropertyValue{}, nil, opts.awaitErrorf(awaitCtx, "URN of resource", path)
+				}
 				return resource.PropertyValue{}, nil, err
 			}
 			contract.Assertf(known, "URN must be known")
 			contract.Assertf(!secretURN, "URN must not be secret")
 
 			if custom, ok := v.(CustomResource); ok {
-				id, _, secretID, err := custom.ID().awaitID(context.Background())
+				awaitCtx, cancel := opts.awaitContext()
+				done := traceAwait("ID", path)
+				id, _, secretID, err := custom.ID().awaitID(awaitCtx)
+				done()
+				cancel()
 				if err != nil {
+					if awaitCtx.Err() != nil {
+						return resource.PropertyValue{}, nil, opts.awaitErrorf(awaitCtx, "ID of resource", path)
+					}
 					return resource.PropertyValue{}, nil, err
 				}
-				contract.Assertf(!secretID, "CustomResource must not have a secret ID")
-
-				return resource.MakeCustomResourceReference(resource.URN(urn), resource.ID(id), ""), deps, nil
+				ref := resource.MakeCustomResourceReference(resource.URN(urn), resource.ID(id), "")
+				if secretID {
+					// Some providers produce secret IDs for sensitive resources. The
+					// reference itself cannot carry secretness, so mark the containing
+					// property as secret rather than crashing.
+					if opts != nil && opts.ErrorOnSecret {
+						if path == "" {
+							path = "."
+						}
+						return resource.PropertyValue{}, nil,
+							fmt.Errorf("unexpected secret value at %s: the property may not be a secret", path)
+					}
+					ref = resource.MakeSecret(ref)
+				}
+				return ref, deps, nil
 			}
 
 			return resource.MakeComponentResourceReference(resource.URN(urn), ""), deps, nil
+		case encoding.TextMarshaler:
+			// String-kind named types (e.g. template.HTML and the other html/template
+			// safe-string types) always marshal as their underlying string: their value is
+			// the string, and a custom textual form must not change it.
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+				return resource.NewProperty(rv.String()), deps, nil
+			}
+			// Enum-like types (e.g. stringer-generated enums) that implement
+			// encoding.TextMarshaler marshal as their textual form rather than through the
+			// numeric/string reflection default.
+			text, err := v.MarshalText()
+			if err != nil {
+				return resource.PropertyValue{}, nil, err
+			}
+			return resource.NewProperty(string(text)), deps, nil
+		case PropertyMapper:
+			// Custom map implementations (ordered maps, sync.Map wrappers, etc.) expose
+			// their entries through ToPropertyMap rather than requiring conversion to a
+			// plain Go map up front.
+			return marshalInputAtDepth(v.ToPropertyMap(), anyType, opts, false, path, depth+1)
+		}
+
+		// Types registered with RegisterStringType (e.g. arbitrary-precision decimals)
+		// marshal as their String() form so their values never pass through float64.
+		if _, ok := stringTypeParser(reflect.TypeOf(v)); ok {
+			return resource.NewProperty(v.(fmt.Stringer).String()), deps, nil
 		}
 
 		if destType.Kind() == reflect.Interface {
@@ -422,6 +1247,11 @@ func marshalInputOptionsImpl(v any,
 		case reflect.Bool:
 			return resource.NewProperty(rv.Bool()), deps, nil
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if enumWireForm(rv.Type()) == EnumWireFormString {
+				if s, ok := v.(fmt.Stringer); ok {
+					return resource.NewProperty(s.String()), deps, nil
+				}
+			}
 			return resource.NewProperty(float64(rv.Int())), deps, nil
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			return resource.NewProperty(float64(rv.Uint())), deps, nil
@@ -440,76 +1270,460 @@ func marshalInputOptionsImpl(v any,
 		case reflect.String:
 			return resource.NewProperty(rv.String()), deps, nil
 		case reflect.Array, reflect.Slice:
-			if rv.IsNil() {
+			if rv.Kind() == reflect.Array {
+				// Fixed-size array-backed ID types such as uuid.UUID implement
+				// encoding.TextMarshaler; emit their canonical textual form rather than a
+				// numeric array.
+				if tm, ok := v.(encoding.TextMarshaler); ok {
+					text, err := tm.MarshalText()
+					if err != nil {
+						return resource.PropertyValue{}, nil, err
+					}
+					return resource.NewProperty(string(text)), deps, nil
+				}
+			} else if rv.IsNil() {
 				return resource.PropertyValue{}, deps, nil
 			}
 
-			destElem := destType.Elem()
+			// Byte slices marshal as base64 strings by default rather than as number
+			// arrays, which bloat the message and are almost never what providers expect.
+			// Fields tagged with the "bytearray" flag keep the number-array form.
+			if rv.Kind() == reflect.Slice && rv.Type().Elem() == byteType && rv.Type() != rawMessageType {
+				return resource.NewProperty(base64.StdEncoding.EncodeToString(rv.Bytes())), deps, nil
+			}
 
-			// If an array or a slice, create a new array by recursing into elements.
+			// A registered tuple type carries a positional schema: enforce its arity and
+			// marshal each element against its declared position type.
+			if schema, ok := tupleSchema(rv.Type()); ok {
+				if rv.Len() != len(schema) {
+					return resource.PropertyValue{}, nil, fmt.Errorf(
+						"expected a tuple of %d elements at %s, got %d", len(schema), path, rv.Len())
+				}
+				arr := make([]resource.PropertyValue, 0, len(schema))
+				for i := 0; i < rv.Len(); i++ {
+					ev := rv.Index(i).Interface()
+					if ev != nil {
+						et := reflect.TypeOf(ev)
+						if in, ok := ev.(Input); ok {
+							et = in.ElementType()
+						}
+						if !et.AssignableTo(schema[i]) {
+							return resource.PropertyValue{}, nil, fmt.Errorf(
+								"expected a %v at %s[%d], got a %v", schema[i], path, i, et)
+						}
+					}
+					e, d, err := marshalInputAtDepth(ev, schema[i], opts, false,
+						fmt.Sprintf("%s[%d]", path, i), depth+1)
+					if err != nil {
+						return resource.PropertyValue{}, nil, err
+					}
+					arr = append(arr, e)
+					deps = append(deps, d...)
+				}
+				return resource.NewProperty(arr), deps, nil
+			}
+
+			// Use the destination's element type when the destination is itself a list; for
+			// named slice types (`type Ports []int`) marshaled against a mismatched or
+			// generic destination, fall back to the value's own element type so no elements
+			// are dropped.
+			destElem := rv.Type().Elem()
+			if destType.Kind() == reflect.Array || destType.Kind() == reflect.Slice {
+				destElem = destType.Elem()
+			}
+
+			// If an array or a slice, create a new array by recursing into elements. Elements
+			// of interface-typed slices ([]any) are marshaled as their own dynamic types, so
+			// mixed-type slices serialize each element faithfully.
 			arr := make([]resource.PropertyValue, 0, rv.Len())
 			for i := 0; i < rv.Len(); i++ {
 				elem := rv.Index(i)
-				e, d, err := marshalInputOptions(elem.Interface(), destElem, opts)
+				e, d, err := marshalInputAtDepth(elem.Interface(), destElem, opts, false,
+					fmt.Sprintf("%s[%d]", path, i), depth+1)
 				if err != nil {
 					return resource.PropertyValue{}, nil, err
 				}
 				arr = append(arr, e)
 				deps = append(deps, d...)
 			}
+			if opts != nil && opts.RequireHomogeneousArrays && destElem.Kind() == reflect.Interface {
+				for i := 1; i < len(arr); i++ {
+					if arr[i].TypeString() != arr[0].TypeString() {
+						return resource.PropertyValue{}, nil, fmt.Errorf(
+							"expected a homogeneous array at %s: element %d is a %s, not a %s",
+							path, i, arr[i].TypeString(), arr[0].TypeString())
+					}
+				}
+			}
 			return resource.NewProperty(arr), deps, nil
 		case reflect.Map:
-			if rv.Type().Key().Kind() != reflect.String {
+			// Map keys must be strings on the wire. Integer keys are stringified in base 10
+			// when StringifyNonStringMapKeys is set; other key types are rejected.
+			keyString := func(key reflect.Value) string { return key.String() }
+			switch kind := rv.Type().Key().Kind(); {
+			case kind == reflect.String:
+			case opts != nil && opts.StringifyNonStringMapKeys &&
+				kind >= reflect.Int && kind <= reflect.Int64:
+				keyString = func(key reflect.Value) string { return strconv.FormatInt(key.Int(), 10) }
+			case opts != nil && opts.StringifyNonStringMapKeys &&
+				kind >= reflect.Uint && kind <= reflect.Uint64:
+				keyString = func(key reflect.Value) string { return strconv.FormatUint(key.Uint(), 10) }
+			default:
 				return resource.PropertyValue{}, nil,
 					fmt.Errorf("expected map keys to be strings; got %v", rv.Type().Key())
 			}
 
-			if rv.IsNil() {
-				return resource.PropertyValue{}, deps, nil
-			}
+			if rv.IsNil() {
+				return resource.PropertyValue{}, deps, nil
+			}
+
+			// As with slices, prefer the destination's element type but tolerate named map
+			// types (`type Tags map[string]string`) whose destination is not map-shaped.
+			destElem := rv.Type().Elem()
+			if destType.Kind() == reflect.Map {
+				destElem = destType.Elem()
+			}
+
+			// Fast path: a string-keyed map whose element type is a concrete primitive (e.g. a
+			// plain map[string]string of tags) cannot contain Inputs or Outputs, so we can
+			// convert each value directly and skip the per-element input check and await
+			// machinery.
+			if rv.Type().Key().Kind() == reflect.String {
+				if pv, ok := marshalPlainPrimitiveMap(rv, destElem); ok {
+					return pv, deps, nil
+				}
+			}
+
+			// Recurse into the values.
+			obj := resource.PropertyMap{}
+			for _, key := range rv.MapKeys() {
+				value := rv.MapIndex(key)
+				k := keyString(key)
+				mv, d, err := marshalInputAtDepth(value.Interface(), destElem, opts, false,
+					path+"."+k, depth+1)
+				if err != nil {
+					return resource.PropertyValue{}, nil, err
+				}
+				if !mv.IsNull() {
+					obj[resource.PropertyKey(k)] = mv
+				}
+				deps = append(deps, d...)
+			}
+			return resource.NewProperty(obj), deps, nil
+		case reflect.Struct:
+			// Generic option wrappers (an Option[T] with a `Value() (T, bool)` method)
+			// marshal as their inner value when present and null when absent, mirroring the
+			// OptionalValue interface for non-generic wrappers.
+			if inner, valid, ok := genericOptionValue(v); ok {
+				if !valid {
+					return resource.PropertyValue{}, deps, nil
+				}
+				return marshalInputAtDepth(inner, anyType, opts, false, path, depth+1)
+			}
+
+			// When opted in, a struct with no `pulumi`-tagged fields that implements
+			// fmt.Stringer marshals via String() rather than as an empty object.
+			if opts != nil && opts.UseStringerForUnknownStructs {
+				if s, isStringer := v.(fmt.Stringer); isStringer && !hasPulumiTaggedFields(rv.Type()) {
+					return resource.NewProperty(s.String()), deps, nil
+				}
+			}
+
+			obj := resource.PropertyMap{}
+			var inline []resource.PropertyMap
+			typ := rv.Type()
+			getMappedField := internal.MapStructTypes(typ, destType)
+			for i := 0; i < typ.NumField(); i++ {
+				// Skip embedded framework state (ResourceState et al.): its internals are
+				// not resource properties and must never be marshaled.
+				if f := typ.Field(i); f.Anonymous && isResourceStateType(f.Type) {
+					continue
+				}
+
+				destField, _ := getMappedField(reflect.Value{}, i)
+
+				// A `pulumi:",inline"` map field is a catch-all: its entries are spliced
+				// into the parent object rather than nested under a key. Collect them and
+				// merge after the loop so explicitly declared fields win on collision.
+				if hasTagFlag(destField.Tag, "inline") {
+					if destField.Type.Kind() != reflect.Map || destField.Type.Key().Kind() != reflect.String {
+						return resource.PropertyValue{}, nil, fmt.Errorf(
+							"inline field %s must be a map with string keys", destField.Name)
+					}
+					fv, d, err := marshalInputAtDepth(rv.Field(i).Interface(), anyType, opts, false, path, depth+1)
+					if err != nil {
+						return resource.PropertyValue{}, nil, err
+					}
+					if fv.IsObject() {
+						inline = append(inline, fv.ObjectValue())
+					}
+					deps = append(deps, d...)
+					continue
+				}
+
+				tag, omitEmpty, ok := propertyTagName(destField, opts != nil && opts.UseJSONTagsFallback)
+				if !ok {
+					continue
+				}
+				if omitEmpty && rv.Field(i).IsZero() {
+					continue
+				}
+
+				// Skip context.Context fields outright: they show up when request structs are
+				// reused as args, carry no marshalable data, and reflecting over a context's
+				// internals would otherwise fail in a far more confusing way.
+				if destField.Type == contextType || destField.Type.Implements(contextType) {
+					continue
+				}
+
+				val := rv.Field(i).Interface()
+				if name, ok := tagOption(destField.Tag, "serializer"); ok {
+					sv, err := callFieldSerializer(rv, name, destField.Name)
+					if err != nil {
+						return resource.PropertyValue{}, nil, err
+					}
+					val = preSerialized{sv}
+				}
+				if d, ok := val.(time.Duration); ok && hasTagFlag(destField.Tag, "durationnanos") {
+					val = durationNanos(d)
+				}
+				if b, ok := val.([]byte); ok && hasTagFlag(destField.Tag, "bytearray") {
+					val = numericBytes(b)
+				}
+				if hasTagFlag(destField.Tag, "secret") {
+					val = secretFlagged{val}
+				}
+				fv, d, err := marshalInputAtDepth(val, destField.Type, opts, false,
+					path+"."+tag, depth+1)
+				if err != nil {
+					return resource.PropertyValue{}, nil, err
+				}
+
+				if !fv.IsNull() {
+					obj[resource.PropertyKey(tag)] = fv
+				}
+				deps = append(deps, d...)
+			}
+			for _, m := range inline {
+				for k, fv := range m {
+					if _, exists := obj[k]; !exists {
+						obj[k] = fv
+					}
+				}
+			}
+			return resource.NewProperty(obj), deps, nil
+		}
+		return resource.PropertyValue{}, nil, fmt.Errorf("unrecognized input property type: %v (%T)", v, v)
+	}
+}
+
+// marshalOrderedMap marshals an OrderedMap's entries, in insertion order, as an array of
+// {key, value} pair objects.
+func marshalOrderedMap(m *OrderedMap, opts *marshalOptions, path string, depth int, deps []Resource,
+) (resource.PropertyValue, []Resource, error) {
+	pairs := make([]resource.PropertyValue, 0, len(m.keys))
+	for _, k := range m.keys {
+		ev, d, err := marshalInputAtDepth(m.values[k], anyType, opts, false, path+"."+k, depth+1)
+		if err != nil {
+			return resource.PropertyValue{}, nil, err
+		}
+		deps = append(deps, d...)
+		pairs = append(pairs, resource.NewProperty(resource.PropertyMap{
+			"key":   resource.NewProperty(k),
+			"value": ev,
+		}))
+	}
+	return resource.NewProperty(pairs), deps, nil
+}
+
+// durationNanos wraps a time.Duration field whose `pulumi` tag carries the "durationnanos"
+// flag, marking it to marshal in its raw nanosecond numeric form rather than as a duration
+// string.
+type durationNanos time.Duration
+
+// numericBytes wraps a []byte field whose `pulumi` tag carries the "bytearray" flag,
+// marking it to marshal in the legacy number-array form rather than as a base64 string.
+type numericBytes []byte
+
+// preSerialized wraps a property value produced by a field's custom serializer method
+// (tagged `pulumi:"name,serializer=Method"`) so it passes through marshaling untouched.
+type preSerialized struct {
+	value resource.PropertyValue
+}
+
+// secretFlagged wraps the value of a struct field whose `pulumi` tag carries the "secret"
+// flag, marking it to marshal as a secret regardless of whether the source value was
+// wrapped with ToSecret. Generated SDKs use the flag to declaratively protect sensitive
+// fields so users cannot forget to wrap them.
+type secretFlagged struct{ value any }
+
+// hasTagFlag reports whether the given `pulumi` struct tag carries the named flag
+// (e.g. `pulumi:"timeout,durationnanos"`).
+func hasTagFlag(tag reflect.StructTag, flag string) bool {
+	parts := strings.Split(tag.Get("pulumi"), ",")
+	return slices.Contains(parts[1:], flag)
+}
+
+// tagOption returns the value of a `name=value` option in the given `pulumi` struct tag
+// (e.g. `pulumi:"foo,serializer=SerializeFoo"`).
+func tagOption(tag reflect.StructTag, name string) (string, bool) {
+	for _, part := range strings.Split(tag.Get("pulumi"), ",")[1:] {
+		if v, ok := strings.CutPrefix(part, name+"="); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// callFieldSerializer invokes the custom serializer for a struct field tagged
+// `pulumi:"name,serializer=Method"`. The named method must be declared on the struct (with
+// a value or pointer receiver) and have the signature
+//
+//	func() (resource.PropertyValue, error)
+//
+// Its result is emitted for the field verbatim, giving per-field control over
+// serialization without a global type registry.
+func callFieldSerializer(recv reflect.Value, name, field string) (resource.PropertyValue, error) {
+	m := recv.MethodByName(name)
+	if !m.IsValid() {
+		// Pointer-receiver methods are not in the value's method set; retry through an
+		// addressable copy.
+		ptr := reflect.New(recv.Type())
+		ptr.Elem().Set(recv)
+		m = ptr.MethodByName(name)
+	}
+	if !m.IsValid() {
+		return resource.PropertyValue{}, fmt.Errorf("serializer method %s for field %s not found", name, field)
+	}
+	mt := m.Type()
+	if mt.NumIn() != 0 || mt.NumOut() != 2 || mt.Out(0) != propertyValueType || mt.Out(1) != errorType {
+		return resource.PropertyValue{}, fmt.Errorf(
+			"serializer method %s for field %s must have signature func() (resource.PropertyValue, error)",
+			name, field)
+	}
+	out := m.Call(nil)
+	if !out[1].IsNil() {
+		return resource.PropertyValue{}, out[1].Interface().(error)
+	}
+	return out[0].Interface().(resource.PropertyValue), nil
+}
+
+// callFieldDeserializer invokes the custom deserializer for a struct field tagged
+// `pulumi:"name,deserializer=Method"`, passing it the field's raw property value. The
+// named method must be declared on the destination struct and have the signature
+//
+//	func(resource.PropertyValue) error
+//
+// and is responsible for populating the field itself.
+func callFieldDeserializer(recv reflect.Value, name, field string, v resource.PropertyValue) error {
+	m := recv.Addr().MethodByName(name)
+	if !m.IsValid() {
+		return fmt.Errorf("deserializer method %s for field %s not found", name, field)
+	}
+	mt := m.Type()
+	if mt.NumIn() != 1 || mt.In(0) != propertyValueType || mt.NumOut() != 1 || mt.Out(0) != errorType {
+		return fmt.Errorf(
+			"deserializer method %s for field %s must have signature func(resource.PropertyValue) error",
+			name, field)
+	}
+	if err := m.Call([]reflect.Value{reflect.ValueOf(v)})[0]; !err.IsNil() {
+		return err.Interface().(error)
+	}
+	return nil
+}
+
+// hasPulumiTaggedFields reports whether any field of the given struct type carries a
+// `pulumi` tag.
+func hasPulumiTaggedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if tag := strings.Split(t.Field(i).Tag.Get("pulumi"), ",")[0]; tag != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isResourceStateType returns true if the given type is one of the embeddable framework
+// state structs: ResourceState, CustomResourceState, or ProviderResourceState.
+func isResourceStateType(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeFor[ResourceState](),
+		reflect.TypeFor[CustomResourceState](),
+		reflect.TypeFor[ProviderResourceState]():
+		return true
+	}
+	return false
+}
+
+// marshalPlainPrimitiveMap marshals a map whose element type is a concrete primitive
+// (bool, integer, float, or string) that does not implement Input. Values of such maps
+// cannot be Outputs or carry dependencies, so they can be converted directly without
+// going through the general marshalInputOptions machinery. The boolean result reports
+// whether the map was eligible for this fast path.
+func marshalPlainPrimitiveMap(rv reflect.Value, destElem reflect.Type) (resource.PropertyValue, bool) {
+	// Leave non-string keys to the general path so it can report the error.
+	if rv.Type().Key().Kind() != reflect.String {
+		return resource.PropertyValue{}, false
+	}
+
+	elem := rv.Type().Elem()
 
-			destElem := destType.Elem()
+	// Named primitive types (e.g. pulumi.String) may implement Input; those must go
+	// through the general path so they are awaited and converted appropriately.
+	if elem.Implements(inputType) || reflect.PointerTo(elem).Implements(inputType) {
+		return resource.PropertyValue{}, false
+	}
 
-			// For maps, only support string-based keys, and recurse into the values.
-			obj := resource.PropertyMap{}
-			for _, key := range rv.MapKeys() {
-				value := rv.MapIndex(key)
-				mv, d, err := marshalInputOptions(value.Interface(), destElem, opts)
-				if err != nil {
-					return resource.PropertyValue{}, nil, err
-				}
-				if !mv.IsNull() {
-					obj[resource.PropertyKey(key.String())] = mv
-				}
-				deps = append(deps, d...)
-			}
-			return resource.NewProperty(obj), deps, nil
-		case reflect.Struct:
-			obj := resource.PropertyMap{}
-			typ := rv.Type()
-			getMappedField := internal.MapStructTypes(typ, destType)
-			for i := 0; i < typ.NumField(); i++ {
-				destField, _ := getMappedField(reflect.Value{}, i)
-				tag := destField.Tag.Get("pulumi")
-				tag = strings.Split(tag, ",")[0] // tagName,flag => tagName
-				if tag == "" {
-					continue
-				}
+	// Types that control their own serialization via encoding.TextMarshaler (e.g.
+	// text-marshaled enums) must go through the general path as well.
+	if elem.Implements(textMarshalerType) || reflect.PointerTo(elem).Implements(textMarshalerType) {
+		return resource.PropertyValue{}, false
+	}
 
-				fv, d, err := marshalInputOptions(rv.Field(i).Interface(), destField.Type, opts)
-				if err != nil {
-					return resource.PropertyValue{}, nil, err
-				}
+	// So must types with a registered wire-form override: string-form enums and types
+	// registered with RegisterStringType marshal as strings, not as their raw kind.
+	if enumWireForm(elem) == EnumWireFormString {
+		return resource.PropertyValue{}, false
+	}
+	if _, ok := stringTypeParser(elem); ok {
+		return resource.PropertyValue{}, false
+	}
 
-				if !fv.IsNull() {
-					obj[resource.PropertyKey(tag)] = fv
-				}
-				deps = append(deps, d...)
-			}
-			return resource.NewProperty(obj), deps, nil
-		}
-		return resource.PropertyValue{}, nil, fmt.Errorf("unrecognized input property type: %v (%T)", v, v)
+	// time.Duration is well-known: it marshals as its formatted string (e.g. "1h30m0s")
+	// in every position, not as raw nanoseconds.
+	if elem == durationType {
+		return resource.PropertyValue{}, false
+	}
+
+	// Mirror the general path's assignability requirements: the destination element type
+	// must either be an interface or accept the map's element type.
+	if destElem.Kind() != reflect.Interface && !elem.AssignableTo(destElem) && !elem.ConvertibleTo(destElem) {
+		return resource.PropertyValue{}, false
+	}
+
+	var convert func(reflect.Value) resource.PropertyValue
+	//nolint:exhaustive // Only primitive kinds are eligible for the fast path.
+	switch elem.Kind() {
+	case reflect.Bool:
+		convert = func(v reflect.Value) resource.PropertyValue { return resource.NewProperty(v.Bool()) }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		convert = func(v reflect.Value) resource.PropertyValue { return resource.NewProperty(float64(v.Int())) }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		convert = func(v reflect.Value) resource.PropertyValue { return resource.NewProperty(float64(v.Uint())) }
+	case reflect.Float32, reflect.Float64:
+		convert = func(v reflect.Value) resource.PropertyValue { return resource.NewProperty(v.Float()) }
+	case reflect.String:
+		convert = func(v reflect.Value) resource.PropertyValue { return resource.NewProperty(v.String()) }
+	default:
+		return resource.PropertyValue{}, false
+	}
+
+	obj := make(resource.PropertyMap, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		obj[resource.PropertyKey(iter.Key().String())] = convert(iter.Value())
 	}
+	return resource.NewProperty(obj), true
 }
 
 func unmarshalResourceReference(ctx *Context, ref resource.ResourceReference) (Resource, error) {
@@ -540,6 +1754,11 @@ func unmarshalResourceReference(ctx *Context, ref resource.ResourceReference) (R
 			resourcePackage := resourcePackageV.(ResourcePackage)
 			return resourcePackage.ConstructProvider(ctx, resName, string(resType), string(ref.URN))
 		}
+		if ctx.state.info.strictResourceReferences {
+			return nil, fmt.Errorf(
+				"no resource package %q registered for version %q: reference %q cannot be rehydrated as a typed provider",
+				pkgName, ref.PackageVersion, ref.URN)
+		}
 		id, _ := ref.IDString()
 		return ctx.newDependencyProviderResource(URN(ref.URN), ID(id)), nil
 	}
@@ -549,12 +1768,54 @@ func unmarshalResourceReference(ctx *Context, ref resource.ResourceReference) (R
 		resourceModule := resourceModuleV.(ResourceModule)
 		return resourceModule.Construct(ctx, resName, string(resType), string(ref.URN))
 	}
+	if ctx.state.info.strictResourceReferences {
+		return nil, fmt.Errorf(
+			"no resource module %q registered for version %q: reference %q cannot be rehydrated as a typed resource",
+			modName, ref.PackageVersion, ref.URN)
+	}
 	if id, hasID := ref.IDString(); hasID {
 		return ctx.newDependencyCustomResource(URN(ref.URN), ID(id)), nil
 	}
 	return ctx.newDependencyResource(URN(ref.URN)), nil
 }
 
+// constructTypedResourceReference attempts to construct a correctly-typed resource for the
+// given resource reference by consulting the registered ResourceModule for the reference's
+// module without a version constraint. It returns the value to assign to a destination of
+// type destType and true if construction succeeded and produced an assignable resource.
+func constructTypedResourceReference(
+	ctx *Context, ref resource.ResourceReference, destType reflect.Type,
+) (reflect.Value, bool) {
+	resTypeString := ref.Type
+	if resTypeString == "" && ref.URN.IsValid() {
+		resTypeString = string(ref.URN.Type())
+	}
+	resType := tokens.Type(resTypeString)
+
+	resName := ref.Name
+	if resName == "" && ref.URN.IsValid() {
+		resName = ref.URN.Name()
+	}
+
+	resourceModuleV, ok := resourceModules.Load(resType.Module().String(), nullVersion)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	res, err := resourceModuleV.(ResourceModule).Construct(ctx, resName, string(resType), string(ref.URN))
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	resV := reflect.ValueOf(res)
+	if resV.Kind() == reflect.Pointer && resV.Elem().Type().AssignableTo(destType) {
+		return resV.Elem(), true
+	}
+	if resV.Type().AssignableTo(destType) {
+		return resV, true
+	}
+	return reflect.Value{}, false
+}
+
 func unmarshalPropertyValue(ctx *Context, v resource.PropertyValue) (any, bool, error) {
 	switch {
 	case v.IsComputed():
@@ -607,7 +1868,7 @@ func unmarshalPropertyValue(ctx *Context, v resource.PropertyValue) (any, bool,
 		case asset.IsText():
 			return NewStringAsset(asset.Text), false, nil
 		case asset.IsURI():
-			return NewRemoteAsset(asset.URI), false, nil
+			return assetForURI(asset.URI), false, nil
 		}
 		return nil, false, errors.New("expected asset to be one of File, String, or Remote; got none")
 	case v.IsArchive():
@@ -628,7 +1889,7 @@ func unmarshalPropertyValue(ctx *Context, v resource.PropertyValue) (any, bool,
 		case archive.IsPath():
 			return NewFileArchive(archive.Path), secret, nil
 		case archive.IsURI():
-			return NewRemoteArchive(archive.URI), secret, nil
+			return archiveForURI(archive.URI), secret, nil
 		}
 		return nil, false, errors.New("expected asset to be one of File, String, or Remote; got none")
 	case v.IsResourceReference():
@@ -647,12 +1908,34 @@ func unmarshalPropertyValue(ctx *Context, v resource.PropertyValue) (any, bool,
 // `OutputString`). It will use `pulumi.Output` for values that are either Computed (will always be a
 // `pulumi.AnyOutput`), secret, or an output property value.
 func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
+	return unmarshalPropertyMapTyped(ctx, v, nil)
+}
+
+// hintOutputType maps a plain element type hint (e.g. string) to the registered output
+// type for that element type (StringOutput), falling back to AnyOutput for nil hints and
+// unregistered types.
+func hintOutputType(hint reflect.Type) reflect.Type {
+	if hint != nil {
+		if ot := internal.ConcreteTypeToOutputType(hint); ot != nil {
+			return ot
+		}
+	}
+	return anyOutputType
+}
+
+// unmarshalPropertyMapTyped is a schema-aware variant of unmarshalPropertyMap: types maps
+// property keys to the expected element type of the corresponding property (e.g.
+// reflect.TypeFor[string]() for a string property). Unknown or computed values for hinted
+// properties are created with the matching output type (a StringOutput rather than an
+// AnyOutput) so typed consumers don't need casts. Properties without a hint behave exactly
+// as in unmarshalPropertyMap.
+func unmarshalPropertyMapTyped(ctx *Context, v resource.PropertyMap, types map[string]reflect.Type) (Map, error) {
 	if v == nil {
 		return nil, nil
 	}
 
-	var unmarshal func(resource.PropertyValue) (Input, error)
-	unmarshal = func(v resource.PropertyValue) (Input, error) {
+	var unmarshal func(resource.PropertyValue, reflect.Type) (Input, error)
+	unmarshal = func(v resource.PropertyValue, hint reflect.Type) (Input, error) {
 		switch {
 		case v.IsNull():
 			return nil, nil
@@ -666,7 +1949,7 @@ func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
 			a := v.ArrayValue()
 			r := make(Array, len(a))
 			for i, v := range a {
-				uv, err := unmarshal(v)
+				uv, err := unmarshal(v, nil)
 				if err != nil {
 					return nil, err
 				}
@@ -684,7 +1967,7 @@ func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
 			case asset.IsText():
 				return NewStringAsset(asset.Text), nil
 			case asset.IsURI():
-				return NewRemoteAsset(asset.URI), nil
+				return assetForURI(asset.URI), nil
 			}
 			return nil, errors.New("expected asset to be one of File, String, or Remote; got none")
 		case v.IsArchive():
@@ -705,7 +1988,7 @@ func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
 			case archive.IsPath():
 				return NewFileArchive(archive.Path), nil
 			case archive.IsURI():
-				return NewRemoteArchive(archive.URI), nil
+				return archiveForURI(archive.URI), nil
 			}
 			return nil, errors.New("expected archive to be one of Assets, File, or Remote; got none")
 		case v.IsResourceReference():
@@ -717,13 +2000,11 @@ func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
 			return output, nil
 
 		case v.IsComputed():
-			typ := reflect.TypeFor[any]()
-			typ = getOutputType(typ)
-			output := ctx.newOutput(typ)
+			output := ctx.newOutput(hintOutputType(hint))
 			internal.ResolveOutput(output, nil, false, false, nil /* deps */)
 			return output, nil
 		case v.IsSecret():
-			element, err := unmarshal(v.SecretValue().Element)
+			element, err := unmarshal(v.SecretValue().Element, hint)
 			if err != nil {
 				return nil, err
 			}
@@ -738,11 +2019,11 @@ func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
 			secret := v.OutputValue().Secret
 
 			// If the output is known, we can unmarshal it directly else it's nil
-			typ := anyOutputType
+			typ := hintOutputType(hint)
 			var element any
 			if v.OutputValue().Known {
 				var err error
-				element, err = unmarshal(v.OutputValue().Element)
+				element, err = unmarshal(v.OutputValue().Element, hint)
 				if err != nil {
 					return nil, err
 				}
@@ -781,7 +2062,7 @@ func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
 
 	m := make(Map)
 	for k, v := range v {
-		uv, err := unmarshal(v)
+		uv, err := unmarshal(v, types[string(k)])
 		if err != nil {
 			return nil, err
 		}
@@ -790,9 +2071,99 @@ func unmarshalPropertyMap(ctx *Context, v resource.PropertyMap) (Map, error) {
 	return m, nil
 }
 
+// propertyMapEntry is a single key/value pair produced by unmarshalPropertyMapOrdered.
+type propertyMapEntry struct {
+	Key   string
+	Value Input
+}
+
+// unmarshalPropertyMapOrdered is a deterministic variant of unmarshalPropertyMap: it
+// produces the same runtime values but returns the entries as a slice in sorted key
+// order, for callers that need to render properties in a stable order.
+func unmarshalPropertyMapOrdered(ctx *Context, v resource.PropertyMap) ([]propertyMapEntry, error) {
+	m, err := unmarshalPropertyMap(ctx, v)
+	if m == nil || err != nil {
+		return nil, err
+	}
+	entries := make([]propertyMapEntry, 0, len(m))
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		entries = append(entries, propertyMapEntry{Key: k, Value: m[k]})
+	}
+	return entries, nil
+}
+
+// UnmarshalArrayStream unmarshals the elements of an array property value one at a time,
+// invoking each for every element rather than materializing the whole array up front. The
+// callback receives the element's index and a setElem function that unmarshals the element
+// into the pointer it is given; a callback that doesn't call setElem skips the element
+// entirely. Secret wrappers around the array are unwrapped. An error from each aborts the
+// walk. This keeps peak memory flat for consumers of very large arrays.
+func UnmarshalArrayStream(ctx *Context, v resource.PropertyValue, each func(index int, setElem func(dest any) error) error) error {
+	if v.IsNull() {
+		return nil
+	}
+	if v.IsSecret() {
+		return UnmarshalArrayStream(ctx, v.SecretValue().Element, each)
+	}
+	if !v.IsArray() {
+		return fmt.Errorf("expected an array property value, got a %s", v.TypeString())
+	}
+	for i, e := range v.ArrayValue() {
+		setElem := func(dest any) error {
+			rv := reflect.ValueOf(dest)
+			if rv.Kind() != reflect.Pointer || rv.IsNil() {
+				return fmt.Errorf("expected a non-nil pointer destination, got %T", dest)
+			}
+			_, err := unmarshalOutput(ctx, e, rv.Elem())
+			return err
+		}
+		if err := each(i, setElem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // unmarshalOutput unmarshals a single output variable into its runtime representation.
-// returning a bool that indicates secretness
+// returning a bool that indicates secretness.
+//
+// Secretness is aggregated: for arrays and maps, the returned bool is true if any element
+// was secret, but the unmarshaled elements themselves do not individually carry the secret
+// bit — plain Go slices and maps have no way to represent it. This is intentional: callers
+// that re-wrap unmarshaled values selectively must treat the whole aggregate as secret
+// whenever the returned bool is true rather than attempting to recover per-element
+// secretness from the destination.
+// unmarshalOptions controls optional, non-strict behaviors of unmarshaling. A nil
+// *unmarshalOptions yields the default strict behavior.
+type unmarshalOptions struct {
+	// Set to true to accept a number property for a string destination, formatting the
+	// number into the string instead of failing. Some providers return numeric values
+	// (e.g. ports) that users want rendered as text.
+	CoerceNumbersToString bool
+
+	// The number of digits after the decimal point to use when formatting coerced
+	// numbers. Zero or negative uses Go's default (%v) formatting.
+	NumberPrecision int
+
+	// Set to true to continue past per-element type mismatches inside structs, slices, and
+	// maps, accumulating the failures (prefixed with the offending field or index) into a
+	// joined error returned at the end. Elements that do match are still unmarshaled, so a
+	// whole state blob can be validated in one pass.
+	CollectErrors bool
+
+	// Set to true to fall back to a struct field's `json` tag when it has no `pulumi` tag,
+	// honoring "-". This is the unmarshal-side counterpart of the marshal option of the
+	// same name.
+	UseJSONTagsFallback bool
+}
+
 func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value) (bool, error) {
+	return unmarshalOutputOptions(ctx, v, dest, nil)
+}
+
+func unmarshalOutputOptions(
+	ctx *Context, v resource.PropertyValue, dest reflect.Value, opts *unmarshalOptions,
+) (bool, error) {
 	contract.Requiref(dest.CanSet(), "dest", "value must be settable")
 
 	// Check for nils and unknowns. The destination will be left with the zero value.
@@ -840,7 +2211,7 @@ func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value)
 		dest.Set(reflect.ValueOf(archive))
 		return secret, nil
 	case v.IsSecret():
-		if _, err := unmarshalOutput(ctx, v.SecretValue().Element, dest); err != nil {
+		if _, err := unmarshalOutputOptions(ctx, v.SecretValue().Element, dest, opts); err != nil {
 			return false, err
 		}
 		return true, nil
@@ -860,17 +2231,138 @@ func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value)
 		}
 
 		if !resV.Elem().Type().AssignableTo(dest.Type()) {
+			// The reference may have rehydrated as a generic dependency resource (e.g. because it pinned a
+			// package version that isn't registered). If the destination is a concrete generated resource
+			// type, consult the registered ResourceModule without a version constraint so the correctly-typed
+			// resource can be constructed.
+			if typed, ok := constructTypedResourceReference(ctx, v.ResourceReferenceValue(), dest.Type()); ok {
+				dest.Set(typed)
+				return secret, nil
+			}
 			return false, fmt.Errorf("expected a %s, got a resource of type %s", dest.Type(), resV.Type())
 		}
 		dest.Set(resV.Elem())
 		return secret, nil
 	case v.IsOutput():
-		if _, err := unmarshalOutput(ctx, v.OutputValue().Element, dest); err != nil {
+		if _, err := unmarshalOutputOptions(ctx, v.OutputValue().Element, dest, opts); err != nil {
 			return false, err
 		}
 		return v.OutputValue().Secret, nil
 	}
 
+	// json.RawMessage destinations receive the property's JSON representation verbatim,
+	// enabling schema-less inspection of opaque state.
+	if dest.Type() == rawMessageType {
+		raw, secret := rawJSONValue(v)
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return false, err
+		}
+		dest.Set(reflect.ValueOf(json.RawMessage(b)))
+		return secret, nil
+	}
+
+	// Byte slices marshal as base64 strings; decode them back into []byte destinations.
+	// The legacy number-array form still unmarshals through the generic slice path.
+	if dest.Kind() == reflect.Slice && dest.Type().Elem() == byteType &&
+		dest.Type() != rawMessageType && v.IsString() {
+		b, err := base64.StdEncoding.DecodeString(v.StringValue())
+		if err != nil {
+			return false, err
+		}
+		dest.Set(reflect.ValueOf(b).Convert(dest.Type()))
+		return false, nil
+	}
+
+	// Compiled regular expressions marshal as their pattern string; unmarshal them back by
+	// recompiling the pattern, surfacing any compile error.
+	if dest.Type() == regexpType && v.IsString() {
+		re, err := regexp.Compile(v.StringValue())
+		if err != nil {
+			return false, err
+		}
+		dest.Set(reflect.ValueOf(re).Elem())
+		return false, nil
+	}
+
+	// URLs marshal as their string form; unmarshal them back by reparsing the string,
+	// surfacing any parse error.
+	if dest.Type() == urlType && v.IsString() {
+		u, err := url.Parse(v.StringValue())
+		if err != nil {
+			return false, err
+		}
+		dest.Set(reflect.ValueOf(u).Elem())
+		return false, nil
+	}
+
+	// Ordered maps marshal as an array of {key, value} pairs; unmarshal them by replaying
+	// the pairs through Set so that insertion order is restored.
+	if dest.Type() == orderedMapType && v.IsArray() {
+		var m OrderedMap
+		secret := false
+		for i, e := range v.ArrayValue() {
+			if !e.IsObject() {
+				return false, fmt.Errorf("expected ordered map entry %d to be an object, got a %s", i, e.TypeString())
+			}
+			obj := e.ObjectValue()
+			key, has := obj["key"]
+			if !has || !key.IsString() {
+				return false, fmt.Errorf("ordered map entry %d is missing a string \"key\"", i)
+			}
+			val, s, err := unmarshalPropertyValue(ctx, obj["value"])
+			if err != nil {
+				return false, err
+			}
+			secret = secret || s
+			m.Set(key.StringValue(), val)
+		}
+		dest.Set(reflect.ValueOf(m))
+		return secret, nil
+	}
+
+	// Types registered with RegisterStringType unmarshal by their registered parse
+	// function, round-tripping their string form exactly.
+	if parse, ok := stringTypeParser(dest.Type()); ok && v.IsString() {
+		parsed, err := parse(v.StringValue())
+		if err != nil {
+			return false, err
+		}
+		pv := reflect.ValueOf(parsed)
+		if pv.Kind() == reflect.Pointer && pv.Type().Elem() == dest.Type() {
+			pv = pv.Elem()
+		}
+		if !pv.Type().AssignableTo(dest.Type()) {
+			return false, fmt.Errorf("parse function for %v returned a %T", dest.Type(), parsed)
+		}
+		dest.Set(pv)
+		return false, nil
+	}
+
+	// sql.Null*-style optional wrappers marshal as their wrapped value or null; unmarshal
+	// them by populating the wrapper's Value field and recording validity. Null values were
+	// handled above, leaving the wrapper in its zero (invalid) state.
+	if dest.Kind() == reflect.Struct && dest.CanAddr() {
+		if _, ok := dest.Addr().Interface().(OptionalValue); ok {
+			if handled, secret, err := unmarshalOptionalValue(ctx, v, dest); handled {
+				return secret, err
+			}
+		}
+	}
+
+	// Types that marshal as their canonical string form -- text-marshaled enums and
+	// fixed-size array-backed ID types such as uuid.UUID -- unmarshal back through
+	// encoding.TextUnmarshaler. String-kind named types are excluded for symmetry with
+	// marshaling: their raw string value wins over any custom textual form.
+	if v.IsString() && dest.CanAddr() && dest.Kind() != reflect.String {
+		if tu, ok := dest.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := tu.UnmarshalText([]byte(v.StringValue())); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+	}
+
 	// Unmarshal based on the desired type.
 	//nolint:exhaustive // We only need to handle a few types here.
 	switch dest.Kind() {
@@ -881,6 +2373,15 @@ func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value)
 		dest.SetBool(v.BoolValue())
 		return false, nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Durations marshal as duration strings by default; parse that form back.
+		if dest.Type() == durationType && v.IsString() {
+			d, err := time.ParseDuration(v.StringValue())
+			if err != nil {
+				return false, err
+			}
+			dest.SetInt(int64(d))
+			return false, nil
+		}
 		if !v.IsNumber() {
 			return false, fmt.Errorf("expected an %v, got a %s", dest.Type(), v.TypeString())
 		}
@@ -909,6 +2410,12 @@ func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value)
 			} else {
 				dest.SetString(string(ref.URN))
 			}
+		case v.IsNumber() && opts != nil && opts.CoerceNumbersToString:
+			if opts.NumberPrecision > 0 {
+				dest.SetString(strconv.FormatFloat(v.NumberValue(), 'f', opts.NumberPrecision, 64))
+			} else {
+				dest.SetString(fmt.Sprintf("%v", v.NumberValue()))
+			}
 		default:
 			return false, fmt.Errorf("expected a %v, got a %s", dest.Type(), v.TypeString())
 		}
@@ -918,44 +2425,86 @@ func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value)
 			return false, fmt.Errorf("expected a %v, got a %s", dest.Type(), v.TypeString())
 		}
 		arr := v.ArrayValue()
+		// A registered tuple-shaped slice type enforces its schema's arity.
+		if schema, ok := tupleSchema(dest.Type()); ok && len(arr) != len(schema) {
+			return false, fmt.Errorf("expected a tuple of %d elements, got %d", len(schema), len(arr))
+		}
 		slice := reflect.MakeSlice(dest.Type(), len(arr), len(arr))
 		secret := false
+		var errs []error
 		for i, e := range arr {
-			isecret, err := unmarshalOutput(ctx, e, slice.Index(i))
+			isecret, err := unmarshalOutputOptions(ctx, e, slice.Index(i), opts)
 			if err != nil {
-				return false, err
+				if opts == nil || !opts.CollectErrors {
+					return false, err
+				}
+				errs = append(errs, fmt.Errorf("[%d]: %w", i, err))
 			}
 			secret = secret || isecret
 		}
 		dest.Set(slice)
-		return secret, nil
+		return secret, errors.Join(errs...)
 	case reflect.Map:
 		if !v.IsObject() {
 			return false, fmt.Errorf("expected a %v, got a %s", dest.Type(), v.TypeString())
 		}
 
 		keyType, elemType := dest.Type().Key(), dest.Type().Elem()
-		if keyType.Kind() != reflect.String {
+		// String keys assign directly; integer keys (stringified on the marshal side by
+		// StringifyNonStringMapKeys) parse back from their base-10 form.
+		setKey := func(key reflect.Value, k string) error { key.SetString(k); return nil }
+		switch kind := keyType.Kind(); {
+		case kind == reflect.String:
+		case kind >= reflect.Int && kind <= reflect.Int64:
+			setKey = func(key reflect.Value, k string) error {
+				n, err := strconv.ParseInt(k, 10, keyType.Bits())
+				if err != nil {
+					return fmt.Errorf("expected an integer map key, got %q", k)
+				}
+				key.SetInt(n)
+				return nil
+			}
+		case kind >= reflect.Uint && kind <= reflect.Uint64:
+			setKey = func(key reflect.Value, k string) error {
+				n, err := strconv.ParseUint(k, 10, keyType.Bits())
+				if err != nil {
+					return fmt.Errorf("expected an unsigned integer map key, got %q", k)
+				}
+				key.SetUint(n)
+				return nil
+			}
+		default:
 			return false, errors.New("map keys must be assignable from type string")
 		}
 
 		result := reflect.MakeMap(dest.Type())
 		secret := false
+		var errs []error
 		for k, e := range v.ObjectValue() {
 			elem := reflect.New(elemType).Elem()
-			esecret, err := unmarshalOutput(ctx, e, elem)
+			esecret, err := unmarshalOutputOptions(ctx, e, elem, opts)
 			if err != nil {
-				return false, err
+				if opts == nil || !opts.CollectErrors {
+					return false, err
+				}
+				errs = append(errs, fmt.Errorf("%s: %w", string(k), err))
+				continue
 			}
 			secret = secret || esecret
 
 			key := reflect.New(keyType).Elem()
-			key.SetString(string(k))
+			if err := setKey(key, string(k)); err != nil {
+				if opts == nil || !opts.CollectErrors {
+					return false, err
+				}
+				errs = append(errs, fmt.Errorf("%s: %w", string(k), err))
+				continue
+			}
 
 			result.SetMapIndex(key, elem)
 		}
 		dest.Set(result)
-		return secret, nil
+		return secret, errors.Join(errs...)
 	case reflect.Interface:
 		// Tolerate invalid asset or archive values.
 		typ := dest.Type()
@@ -991,21 +2540,59 @@ func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value)
 		return secret, nil
 	case reflect.Struct:
 		typ := dest.Type()
+
+		// A registered tuple type maps array positions onto the struct's settable fields in
+		// declaration order.
+		if schema, ok := tupleSchema(typ); ok && v.IsArray() {
+			arr := v.ArrayValue()
+			if len(arr) != len(schema) {
+				return false, fmt.Errorf("expected a tuple of %d elements, got %d", len(schema), len(arr))
+			}
+			secret := false
+			pos := 0
+			for i := 0; i < typ.NumField() && pos < len(arr); i++ {
+				fieldV := dest.Field(i)
+				if !fieldV.CanSet() {
+					continue
+				}
+				fsecret, err := unmarshalOutputOptions(ctx, arr[pos], fieldV, opts)
+				if err != nil {
+					return false, fmt.Errorf("[%d]: %w", pos, err)
+				}
+				secret = secret || fsecret
+				pos++
+			}
+			return secret, nil
+		}
+
 		if !v.IsObject() {
 			return false, fmt.Errorf("expected a %v, got a %s", dest.Type(), v.TypeString())
 		}
 
 		obj := v.ObjectValue()
 		secret := false
+		var errs []error
+		inlineIndex := -1
+		consumed := map[resource.PropertyKey]struct{}{}
 		for i := 0; i < typ.NumField(); i++ {
 			fieldV := dest.Field(i)
 			if !fieldV.CanSet() {
 				continue
 			}
 
-			tag := typ.Field(i).Tag.Get("pulumi")
-			tag = strings.Split(tag, ",")[0] // tagName,flag => tagName
-			if tag == "" {
+			// A `pulumi:",inline"` map field collects the object's unmatched keys; fill it
+			// once the explicitly declared fields have claimed theirs.
+			if f := typ.Field(i); hasTagFlag(f.Tag, "inline") &&
+				f.Type.Kind() == reflect.Map && f.Type.Key().Kind() == reflect.String {
+				inlineIndex = i
+				continue
+			}
+
+			// Unmarshal by the `pulumi` tag. The `json` tag fallback for fields reused
+			// from API client structs is opt-in, mirroring the marshal side: without the
+			// option, json-only fields keep their zero values as they always have.
+			tag, _, ok := propertyTagName(typ.Field(i), opts != nil && opts.UseJSONTagsFallback)
+			if !ok {
 				continue
 			}
 
@@ -1013,19 +2600,135 @@ func unmarshalOutput(ctx *Context, v resource.PropertyValue, dest reflect.Value)
 			if !ok {
 				continue
 			}
+			consumed[resource.PropertyKey(tag)] = struct{}{}
+
+			// Fields tagged with a "deserializer=Method" option hand their raw property
+			// value to the named method, which populates the field itself.
+			if name, ok := tagOption(typ.Field(i).Tag, "deserializer"); ok {
+				secret = secret || e.ContainsSecrets()
+				if err := callFieldDeserializer(dest, name, typ.Field(i).Name, e); err != nil {
+					if opts == nil || !opts.CollectErrors {
+						return false, err
+					}
+					errs = append(errs, fmt.Errorf("%s: %w", tag, err))
+				}
+				continue
+			}
 
-			osecret, err := unmarshalOutput(ctx, e, fieldV)
+			osecret, err := unmarshalOutputOptions(ctx, e, fieldV, opts)
 			secret = secret || osecret
 			if err != nil {
-				return false, err
+				if opts == nil || !opts.CollectErrors {
+					return false, err
+				}
+				errs = append(errs, fmt.Errorf("%s: %w", tag, err))
 			}
 		}
-		return secret, nil
+		if inlineIndex >= 0 {
+			mt := typ.Field(inlineIndex).Type
+			m := reflect.MakeMap(mt)
+			for k, e := range obj {
+				if _, ok := consumed[k]; ok {
+					continue
+				}
+				elem := reflect.New(mt.Elem()).Elem()
+				esecret, err := unmarshalOutputOptions(ctx, e, elem, opts)
+				secret = secret || esecret
+				if err != nil {
+					if opts == nil || !opts.CollectErrors {
+						return false, err
+					}
+					errs = append(errs, fmt.Errorf("%s: %w", string(k), err))
+					continue
+				}
+
+				key := reflect.New(mt.Key()).Elem()
+				key.SetString(string(k))
+				m.SetMapIndex(key, elem)
+			}
+			if m.Len() > 0 {
+				dest.Field(inlineIndex).Set(m)
+			}
+		}
+		return secret, errors.Join(errs...)
 	default:
 		return false, fmt.Errorf("cannot unmarshal into type %v", dest.Type())
 	}
 }
 
+// unmarshalOptionalValue unmarshals the given property value into an OptionalValue wrapper
+// that follows the sql.Null* field convention ({Value T; Valid bool}). The first result
+// reports whether the wrapper followed the convention and was handled.
+func unmarshalOptionalValue(ctx *Context, v resource.PropertyValue, dest reflect.Value) (bool, bool, error) {
+	valueField := dest.FieldByName("Value")
+	validField := dest.FieldByName("Valid")
+	if !valueField.IsValid() || !valueField.CanSet() || !validField.IsValid() || validField.Kind() != reflect.Bool {
+		return false, false, nil
+	}
+
+	secret, err := unmarshalOutput(ctx, v, valueField)
+	if err != nil {
+		return true, false, err
+	}
+	validField.SetBool(true)
+	return true, secret, nil
+}
+
+// tupleTypes maps tuple-shaped Go types to their positional element schemas.
+var tupleTypes sync.Map // reflect.Type -> []reflect.Type
+
+// RegisterTupleType registers a positional element schema for the given tuple-shaped Go type.
+// Marshaling a value of a registered slice or array type validates that the value has exactly
+// len(elementTypes) elements and that each element is assignable to its position's declared
+// type. Unmarshaling an array into a registered struct type maps positions onto the struct's
+// settable fields in declaration order; unmarshaling into a registered slice type enforces the
+// schema's arity. Panics if the type already has a schema registered.
+func RegisterTupleType(t reflect.Type, elementTypes []reflect.Type) {
+	contract.Requiref(t != nil, "t", "must not be nil")
+	contract.Requiref(len(elementTypes) > 0, "elementTypes", "must not be empty")
+	for i, et := range elementTypes {
+		contract.Requiref(et != nil, "elementTypes", "element %d must not be nil", i)
+	}
+	if _, exists := tupleTypes.LoadOrStore(t, slices.Clone(elementTypes)); exists {
+		panic(fmt.Errorf("a tuple schema for %v is already registered", t))
+	}
+}
+
+// tupleSchema returns the registered positional element schema for the given type, if any.
+func tupleSchema(t reflect.Type) ([]reflect.Type, bool) {
+	if s, ok := tupleTypes.Load(t); ok {
+		return s.([]reflect.Type), true
+	}
+	return nil, false
+}
+
+// stringTypes maps Go types registered to marshal by their string form to their parse
+// functions.
+var stringTypes sync.Map // reflect.Type -> func(string) (any, error)
+
+// RegisterStringType registers a type whose values marshal as their String() form and
+// unmarshal by the given parse function. Arbitrary-precision types (e.g. decimal
+// libraries) use this to round-trip exactly as strings: a registered value never passes
+// through float64, so no precision is lost to the numeric property representation. The
+// type must implement fmt.Stringer, and parse must accept exactly the strings String()
+// produces. Panics if the type is already registered.
+func RegisterStringType(t reflect.Type, parse func(string) (any, error)) {
+	contract.Requiref(t != nil, "t", "must not be nil")
+	contract.Requiref(parse != nil, "parse", "must not be nil")
+	contract.Requiref(t.Implements(stringerType), "t", "%v must implement fmt.Stringer", t)
+	if _, exists := stringTypes.LoadOrStore(t, parse); exists {
+		panic(fmt.Errorf("a string form for %v is already registered", t))
+	}
+}
+
+// stringTypeParser returns the registered parse function for the given type, if any.
+func stringTypeParser(t reflect.Type) (func(string) (any, error), bool) {
+	if p, ok := stringTypes.Load(t); ok {
+		return p.(func(string) (any, error)), true
+	}
+	return nil, false
+}
+
 type Versioned interface {
 	Version() semver.Version
 }
@@ -1090,6 +2793,13 @@ func (vm *versionedMap) Store(key string, value Versioned) error {
 	return nil
 }
 
+func (vm *versionedMap) clear() {
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.versions = make(map[string][]Versioned)
+}
+
 type ResourcePackage interface {
 	Versioned
 	ConstructProvider(ctx *Context, name, typ, urn string) (ProviderResource, error)
@@ -1124,6 +2834,86 @@ func RegisterResourceModule(pkg, mod string, module ResourceModule) {
 	}
 }
 
+// LookupResourcePackage returns the resource package registered for pkg at the given version.
+// The zero version acts as a wildcard and matches the highest registered version; otherwise
+// the search is constrained to the requested major version, preferring an exact match. The
+// second return value reports whether a package was found.
+func LookupResourcePackage(pkg string, version semver.Version) (ResourcePackage, bool) {
+	v, ok := resourcePackages.Load(pkg, version)
+	if !ok {
+		return nil, false
+	}
+	return v.(ResourcePackage), true
+}
+
+// LookupResourceModule returns the resource module registered for mod in pkg at the given
+// version, with the same version-matching semantics as LookupResourcePackage. The second
+// return value reports whether a module was found.
+func LookupResourceModule(pkg, mod string, version semver.Version) (ResourceModule, bool) {
+	v, ok := resourceModules.Load(moduleKey(pkg, mod), version)
+	if !ok {
+		return nil, false
+	}
+	return v.(ResourceModule), true
+}
+
+// resetResourceRegistries clears the process-global resource package and module registries
+// populated by RegisterResourcePackage and RegisterResourceModule. The registries accumulate
+// registrations for the lifetime of the process, so tests that register the same package or
+// module more than once would otherwise panic with a duplicate registration.
+//
+// resetResourceRegistries is intended solely for tests, which reach it through the
+// ResetResourceRegistries hook in export_test.go. It must not be called concurrently with
+// registrations or with operations that consult the registries (e.g. unmarshaling resource
+// references), so tests that use it should not run in parallel with tests that depend on
+// registry state.
+func resetResourceRegistries() {
+	resourcePackages.clear()
+	resourceModules.clear()
+}
+
+// EnumWireForm indicates how a registered named enum type is represented on the wire.
+type EnumWireForm int
+
+const (
+	// EnumWireFormInt marshals an int-backed enum as its underlying integer value. This is
+	// the default for unregistered types.
+	EnumWireFormInt EnumWireForm = iota
+	// EnumWireFormString marshals an int-backed enum as the name reported by its String
+	// method.
+	EnumWireFormString
+)
+
+var enumTypes sync.Map // map[reflect.Type]EnumWireForm
+
+// RegisterEnumType registers a named enum type with the Pulumi runtime along with its wire
+// form. The type must be backed by a signed integer kind — the only kinds the marshaler
+// consults enum registrations for — and types registered with EnumWireFormString must
+// implement fmt.Stringer, since the marshaler emits their String() form instead of the
+// integer value. Invalid registrations panic rather than being silently ignored at
+// marshal time.
+func RegisterEnumType(t reflect.Type, wireForm EnumWireForm) {
+	contract.Requiref(t != nil, "t", "must not be nil")
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// OK
+	default:
+		panic(fmt.Errorf("enum type %v is backed by %v; enums must be backed by a signed integer kind",
+			t, t.Kind()))
+	}
+	if wireForm == EnumWireFormString {
+		contract.Requiref(t.Implements(stringerType), "t", "%v must implement fmt.Stringer", t)
+	}
+	enumTypes.Store(t, wireForm)
+}
+
+func enumWireForm(t reflect.Type) EnumWireForm {
+	if wf, ok := enumTypes.Load(t); ok {
+		return wf.(EnumWireForm)
+	}
+	return EnumWireFormInt
+}
+
 func init() {
 	resourcePackages = versionedMap{versions: make(map[string][]Versioned)}
 	resourceModules = versionedMap{versions: make(map[string][]Versioned)}
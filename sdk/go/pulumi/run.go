@@ -40,6 +40,27 @@ var ErrPlugins = errors.New("pulumi: plugins requested")
 // A RunOption is used to control the behavior of Run and RunErr.
 type RunOption func(*RunInfo)
 
+// WithCaptureRegistrations makes the Context record the type, name, and marshaled inputs
+// of every RegisterResource call, exposed via Context.CapturedRegistrations. This lets
+// record/replay tests assert on exactly what the program sent to the engine without a mock
+// engine. Capture is off by default to avoid retaining inputs in production runs.
+func WithCaptureRegistrations() RunOption {
+	return func(info *RunInfo) {
+		info.captureRegistrations = true
+	}
+}
+
+// WithStrictResourceReferences makes unmarshaling fail when a resource reference names a
+// package or module that has not been registered, instead of silently falling back to an
+// untyped dependency resource. The error names the missing package and version, surfacing
+// "forgot to import the provider SDK" bugs at the reference rather than as a confusing
+// type mismatch later.
+func WithStrictResourceReferences() RunOption {
+	return func(info *RunInfo) {
+		info.strictResourceReferences = true
+	}
+}
+
 // Run executes the body of a Pulumi program, granting it access to a deployment context that it may use
 // to register resources and orchestrate deployment activities.  This connects back to the Pulumi engine using gRPC.
 // If the program fails, the process will be terminated and the function will not return.
@@ -177,7 +198,14 @@ type RunInfo struct {
 	Mocks            MockResourceMonitor
 
 	getPlugins bool
-	engineConn *grpc.ClientConn // Pre-existing engine connection. If set this is used over EngineAddr.
+	// If true, the Context records the marshaled inputs of every RegisterResource call.
+	// See WithCaptureRegistrations.
+	captureRegistrations bool
+	// If true, unmarshaling a resource reference to an unregistered package or module is
+	// an error rather than a fallback to a dependency resource.
+	// See WithStrictResourceReferences.
+	strictResourceReferences bool
+	engineConn               *grpc.ClientConn // Pre-existing engine connection. If set this is used over EngineAddr.
 
 	// If non-nil, wraps the resource monitor client used by Context.
 	wrapResourceMonitorClient func(pulumirpc.ResourceMonitorClient) pulumirpc.ResourceMonitorClient
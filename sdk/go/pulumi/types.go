@@ -150,6 +150,136 @@ func AllWithContext(ctx context.Context, inputs ...any) ArrayOutput {
 	return ToOutputWithContext(ctx, inputs).(ArrayOutput)
 }
 
+// Zipped2 is the element produced by Zip2: the resolved values of two outputs.
+type Zipped2[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zipped3 is the element produced by Zip3: the resolved values of three outputs.
+type Zipped3[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// Zip2 returns an output that resolves to a Zipped2 holding the resolved values of a and
+// b. Secretness and unknownness propagate from both inputs, and the result depends on the
+// union of their dependencies. Unlike consuming two outputs through All, no index or type
+// assertions are required:
+//
+//	endpoint := pulumi.Zip2[string, int](server.Host, server.Port)
+func Zip2[A, B any](a, b Output) pulumix.Output[Zipped2[A, B]] {
+	return Zip2WithContext[A, B](context.Background(), a, b)
+}
+
+// Zip2WithContext returns an output that resolves to a Zipped2 holding the resolved
+// values of a and b. See Zip2.
+func Zip2WithContext[A, B any](ctx context.Context, a, b Output) pulumix.Output[Zipped2[A, B]] {
+	state := internal.NewOutputState(nil, reflect.TypeFor[Zipped2[A, B]]())
+	out := pulumix.Output[Zipped2[A, B]]{OutputState: state}
+	go func() {
+		var zipped Zipped2[A, B]
+		known, secret, deps, err := zipAwait(ctx, []Output{a, b}, func(values []any) error {
+			first, err := zipValue[A](values[0], "first")
+			if err != nil {
+				return err
+			}
+			second, err := zipValue[B](values[1], "second")
+			if err != nil {
+				return err
+			}
+			zipped = Zipped2[A, B]{First: first, Second: second}
+			return nil
+		})
+		if err != nil {
+			internal.RejectOutput(state, err)
+			return
+		}
+		internal.ResolveOutput(out, zipped, known, secret, deps)
+	}()
+	return out
+}
+
+// Zip3 returns an output that resolves to a Zipped3 holding the resolved values of a, b,
+// and c. See Zip2.
+func Zip3[A, B, C any](a, b, c Output) pulumix.Output[Zipped3[A, B, C]] {
+	return Zip3WithContext[A, B, C](context.Background(), a, b, c)
+}
+
+// Zip3WithContext returns an output that resolves to a Zipped3 holding the resolved
+// values of a, b, and c. See Zip2.
+func Zip3WithContext[A, B, C any](ctx context.Context, a, b, c Output) pulumix.Output[Zipped3[A, B, C]] {
+	state := internal.NewOutputState(nil, reflect.TypeFor[Zipped3[A, B, C]]())
+	out := pulumix.Output[Zipped3[A, B, C]]{OutputState: state}
+	go func() {
+		var zipped Zipped3[A, B, C]
+		known, secret, deps, err := zipAwait(ctx, []Output{a, b, c}, func(values []any) error {
+			first, err := zipValue[A](values[0], "first")
+			if err != nil {
+				return err
+			}
+			second, err := zipValue[B](values[1], "second")
+			if err != nil {
+				return err
+			}
+			third, err := zipValue[C](values[2], "third")
+			if err != nil {
+				return err
+			}
+			zipped = Zipped3[A, B, C]{First: first, Second: second, Third: third}
+			return nil
+		})
+		if err != nil {
+			internal.RejectOutput(state, err)
+			return
+		}
+		internal.ResolveOutput(out, zipped, known, secret, deps)
+	}()
+	return out
+}
+
+// zipAwait awaits each output, merging their metadata: the result is known only if every
+// input is known, secret if any input is secret, and depends on the union of the inputs'
+// dependencies. If every input is known, assemble is invoked with their resolved values.
+func zipAwait(
+	ctx context.Context, outputs []Output, assemble func(values []any) error,
+) (known, secret bool, deps []internal.Resource, err error) {
+	known = true
+	values := make([]any, len(outputs))
+	for i, o := range outputs {
+		v, oKnown, oSecret, oDeps, err := internal.AwaitOutput(ctx, o)
+		if err != nil {
+			return false, false, nil, err
+		}
+		values[i] = v
+		known = known && oKnown
+		secret = secret || oSecret
+		deps = append(deps, oDeps...)
+	}
+	if known {
+		if err := assemble(values); err != nil {
+			return false, false, nil, err
+		}
+	}
+	return known, secret, deps, nil
+}
+
+// zipValue converts a resolved output value to the zipped element type, tolerating nil
+// values of non-interface types.
+func zipValue[T any](v any, position string) (T, error) {
+	var zero T
+	if v == nil {
+		return zero, nil
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("zip: expected the %s output to resolve to a %v, got a %T",
+			position, reflect.TypeFor[T](), v)
+	}
+	return t, nil
+}
+
 // JSONMarshal uses "encoding/json".Marshal to serialize the given Output value into a JSON string.
 //
 // JSONMarshal *does not* support marshaling values that contain nested unknowns. You will need to manually create
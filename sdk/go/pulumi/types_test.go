@@ -1341,3 +1341,115 @@ func TestDeferredOutputAny(t *testing.T) {
 	assert.Nil(t, deps)
 	assert.Equal(t, "hello", v)
 }
+
+type barPtrOnlyArgs struct{}
+
+func (barPtrOnlyArgs) ElementType() reflect.Type { return reflect.TypeFor[string]() }
+
+func (*barPtrOnlyArgs) ToBarOutput() Output { return nil }
+
+type BarInput interface {
+	Input
+
+	ToBarOutput() Output
+}
+
+type bazNilElemArgs struct{}
+
+func (bazNilElemArgs) ElementType() reflect.Type { return nil }
+
+func (bazNilElemArgs) ToBazOutput() Output { return nil }
+
+type BazInput interface {
+	Input
+
+	ToBazOutput() Output
+}
+
+type goodArgs struct{}
+
+func (goodArgs) ElementType() reflect.Type { return reflect.TypeFor[string]() }
+
+func (goodArgs) ToGoodOutput() Output { return nil }
+
+type GoodInput interface {
+	Input
+
+	ToGoodOutput() Output
+}
+
+func TestRegisterInputTypeValidation(t *testing.T) {
+	t.Parallel()
+
+	// A nil implementation is rejected up front.
+	assert.PanicsWithError(t, "expected a non-nil input implementation for pulumi.BarInput", func() {
+		RegisterInputType(reflect.TypeFor[BarInput](), nil)
+	})
+
+	// Registering a value whose pointer type implements the interface names the fix.
+	assert.PanicsWithError(t,
+		"expected pulumi.barPtrOnlyArgs to implement interface pulumi.BarInput: "+
+			"only *pulumi.barPtrOnlyArgs implements it; register a pointer",
+		func() {
+			RegisterInputType(reflect.TypeFor[BarInput](), barPtrOnlyArgs{})
+		})
+
+	// An implementation whose ElementType returns nil is incoherent.
+	assert.PanicsWithError(t,
+		"the ElementType method of pulumi.bazNilElemArgs returned nil; it must return the input's element type",
+		func() {
+			RegisterInputType(reflect.TypeFor[BazInput](), bazNilElemArgs{})
+		})
+
+	// A coherent registration succeeds.
+	assert.NotPanics(t, func() {
+		RegisterInputType(reflect.TypeFor[GoodInput](), goodArgs{})
+	})
+}
+
+func TestZip2(t *testing.T) {
+	t.Parallel()
+
+	// One secret input: the zipped result is secret and carries both values.
+	secretHost := ToSecret(String("db.internal"))
+	port := Int(5432).ToIntOutput()
+
+	out := Zip2[string, int](secretHost, port)
+	v, known, secret, _, err := await(out)
+	require.NoError(t, err)
+	assert.True(t, known)
+	assert.True(t, secret)
+	assert.Equal(t, Zipped2[string, int]{First: "db.internal", Second: 5432}, v)
+
+	// One unknown input: the zipped result is unknown and stays secret.
+	unknown := UnsafeUnknownOutput(nil)
+	out = Zip2[string, int](secretHost, unknown)
+	_, known, secret, _, err = await(out)
+	require.NoError(t, err)
+	assert.False(t, known)
+	assert.True(t, secret)
+}
+
+func TestZip3(t *testing.T) {
+	t.Parallel()
+
+	out := Zip3[string, int, bool](
+		String("a").ToStringOutput(),
+		Int(1).ToIntOutput(),
+		Bool(true).ToBoolOutput(),
+	)
+	v, known, secret, _, err := await(out)
+	require.NoError(t, err)
+	assert.True(t, known)
+	assert.False(t, secret)
+	assert.Equal(t, Zipped3[string, int, bool]{First: "a", Second: 1, Third: true}, v)
+
+	// An output resolving to the wrong element type rejects the zipped output.
+	bad := Zip3[string, int, bool](
+		String("a").ToStringOutput(),
+		Int(1).ToIntOutput(),
+		Int(2).ToIntOutput(),
+	)
+	_, _, _, _, err = await(bad)
+	assert.ErrorContains(t, err, "expected the third output to resolve to a bool")
+}
@@ -0,0 +1,189 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// WalkProperties visits every leaf of the given wire-shaped property map (e.g. one produced
+// by marshaling inputs), calling visit with the leaf's fully-qualified path and its value.
+// Nested object properties use dotted paths (e.g. "parent.child") and array elements an index
+// suffix (e.g. "tags[2]"). The walk descends into objects, arrays, secrets, and known output
+// values; leaves under a secret wrapper are delivered wrapped in a secret themselves so that
+// secretness metadata survives the descent, and unknown values (computed placeholders or
+// unknown outputs) are visited as leaves. Keys are visited in sorted order so the traversal
+// is deterministic. Redaction and auditing tools can use this to inspect marshaled properties
+// without unmarshaling them.
+func WalkProperties(m resource.PropertyMap, visit func(path string, v resource.PropertyValue)) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		walkPropertyValue(k, m[resource.PropertyKey(k)], false, visit)
+	}
+}
+
+// SortedPropertyMapJSON renders the given property map as JSON with object keys in sorted
+// order, recursively, so tests can compare marshaled maps against golden strings without
+// each test sorting. Arrays keep their element order. Secrets render as
+// {"secret": <element>}, computed placeholders as "<computed>", output values as
+// {"known": ..., "secret": ..., "value": ...}, and resource references as
+// {"id": ..., "urn": ...}. This is a rendering for comparison and debugging only; the
+// engine's property maps remain unordered.
+func SortedPropertyMapJSON(m resource.PropertyMap) string {
+	var b strings.Builder
+	writeSortedObjectJSON(&b, m)
+	return b.String()
+}
+
+func writeSortedObjectJSON(b *strings.Builder, m resource.PropertyMap) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writeJSONScalar(b, k)
+		b.WriteByte(':')
+		writeSortedValueJSON(b, m[resource.PropertyKey(k)])
+	}
+	b.WriteByte('}')
+}
+
+func writeSortedValueJSON(b *strings.Builder, v resource.PropertyValue) {
+	switch {
+	case v.IsNull():
+		b.WriteString("null")
+	case v.IsBool():
+		writeJSONScalar(b, v.BoolValue())
+	case v.IsNumber():
+		writeJSONScalar(b, v.NumberValue())
+	case v.IsString():
+		writeJSONScalar(b, v.StringValue())
+	case v.IsArray():
+		b.WriteByte('[')
+		for i, e := range v.ArrayValue() {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeSortedValueJSON(b, e)
+		}
+		b.WriteByte(']')
+	case v.IsObject():
+		writeSortedObjectJSON(b, v.ObjectValue())
+	case v.IsSecret():
+		b.WriteString(`{"secret":`)
+		writeSortedValueJSON(b, v.SecretValue().Element)
+		b.WriteByte('}')
+	case v.IsComputed():
+		b.WriteString(`"<computed>"`)
+	case v.IsOutput():
+		out := v.OutputValue()
+		b.WriteString(`{"known":`)
+		writeJSONScalar(b, out.Known)
+		b.WriteString(`,"secret":`)
+		writeJSONScalar(b, out.Secret)
+		b.WriteString(`,"value":`)
+		writeSortedValueJSON(b, out.Element)
+		b.WriteByte('}')
+	case v.IsResourceReference():
+		ref := v.ResourceReferenceValue()
+		b.WriteString(`{"id":`)
+		writeSortedValueJSON(b, ref.ID)
+		b.WriteString(`,"urn":`)
+		writeJSONScalar(b, string(ref.URN))
+		b.WriteByte('}')
+	case v.IsAsset():
+		writeJSONAny(b, v.AssetValue().Serialize())
+	case v.IsArchive():
+		writeJSONAny(b, v.ArchiveValue().Serialize())
+	default:
+		writeJSONScalar(b, fmt.Sprintf("%v", v.V))
+	}
+}
+
+// writeJSONScalar writes a scalar (string, bool, or number) as JSON. Marshaling a scalar
+// cannot fail, so errors are ignored.
+func writeJSONScalar(b *strings.Builder, v any) {
+	encoded, _ := json.Marshal(v)
+	b.Write(encoded)
+}
+
+// writeJSONAny writes an arbitrary serializable value (e.g. a serialized asset) as JSON.
+// encoding/json already renders map keys in sorted order.
+func writeJSONAny(b *strings.Builder, v any) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		writeJSONScalar(b, fmt.Sprintf("%v", v))
+		return
+	}
+	b.Write(encoded)
+}
+
+// walkPropertyValue recurses into a single property value. secret records whether the value
+// sits beneath a secret wrapper, in which case leaves are re-wrapped before being visited.
+func walkPropertyValue(path string, v resource.PropertyValue, secret bool, visit func(string, resource.PropertyValue)) {
+	switch {
+	case v.IsSecret():
+		elem := v.SecretValue().Element
+		if elem.IsObject() || elem.IsArray() || elem.IsSecret() || elem.IsOutput() {
+			walkPropertyValue(path, elem, true, visit)
+			return
+		}
+		visit(path, v)
+	case v.IsOutput():
+		out := v.OutputValue()
+		elem := out.Element
+		if out.Known && (elem.IsObject() || elem.IsArray() || elem.IsSecret() || elem.IsOutput()) {
+			walkPropertyValue(path, elem, secret || out.Secret, visit)
+			return
+		}
+		// Unknown outputs and outputs of scalars are leaves; visiting the output value
+		// itself preserves its dependency and secretness metadata.
+		visit(path, v)
+	case v.IsObject():
+		obj := v.ObjectValue()
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkPropertyValue(path+"."+k, obj[resource.PropertyKey(k)], secret, visit)
+		}
+	case v.IsArray():
+		for i, e := range v.ArrayValue() {
+			walkPropertyValue(fmt.Sprintf("%s[%d]", path, i), e, secret, visit)
+		}
+	default:
+		if secret {
+			v = resource.MakeSecret(v)
+		}
+		visit(path, v)
+	}
+}
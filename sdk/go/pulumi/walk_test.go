@@ -0,0 +1,110 @@
+// Copyright 2026, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulumi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkProperties(t *testing.T) {
+	t.Parallel()
+
+	m := resource.PropertyMap{
+		"plain": resource.NewProperty("a"),
+		"obj": resource.NewProperty(resource.PropertyMap{
+			"inner": resource.NewProperty(1.0),
+			"list": resource.NewProperty([]resource.PropertyValue{
+				resource.NewProperty(true),
+				resource.NewProperty("x"),
+			}),
+		}),
+		"secretScalar": resource.MakeSecret(resource.NewProperty("hunter2")),
+		"secretObj": resource.MakeSecret(resource.NewProperty(resource.PropertyMap{
+			"token": resource.NewProperty("abc"),
+		})),
+		"unknown": resource.MakeComputed(resource.NewProperty("")),
+	}
+
+	visited := map[string]resource.PropertyValue{}
+	var order []string
+	WalkProperties(m, func(path string, v resource.PropertyValue) {
+		visited[path] = v
+		order = append(order, path)
+	})
+
+	assert.Equal(t, resource.NewProperty("a"), visited["plain"])
+	assert.Equal(t, resource.NewProperty(1.0), visited["obj.inner"])
+	assert.Equal(t, resource.NewProperty(true), visited["obj.list[0]"])
+	assert.Equal(t, resource.NewProperty("x"), visited["obj.list[1]"])
+
+	// Secret scalars are visited with their wrapper intact, and leaves beneath a secret
+	// container are re-wrapped so secretness is not lost.
+	assert.Equal(t, resource.MakeSecret(resource.NewProperty("hunter2")), visited["secretScalar"])
+	assert.Equal(t, resource.MakeSecret(resource.NewProperty("abc")), visited["secretObj.token"])
+
+	// Computed placeholders are leaves.
+	assert.True(t, visited["unknown"].IsComputed())
+
+	assert.Len(t, visited, 7)
+
+	// Keys are visited in sorted order for deterministic traversal.
+	assert.Equal(t, []string{
+		"obj.inner", "obj.list[0]", "obj.list[1]",
+		"plain", "secretObj.token", "secretScalar", "unknown",
+	}, order)
+}
+
+func TestSortedPropertyMapJSON(t *testing.T) {
+	t.Parallel()
+
+	m := resource.PropertyMap{
+		"zeta": resource.NewProperty("last"),
+		"alpha": resource.NewProperty(resource.PropertyMap{
+			"nested": resource.NewProperty(resource.PropertyMap{
+				"b": resource.NewProperty(2.0),
+				"a": resource.NewProperty(1.0),
+			}),
+		}),
+		// Arrays keep their element order even when the elements would sort differently.
+		"list": resource.NewProperty([]resource.PropertyValue{
+			resource.NewProperty("z"),
+			resource.NewProperty("a"),
+			resource.NewProperty(resource.PropertyMap{
+				"y": resource.NewProperty(false),
+				"x": resource.NewProperty(true),
+			}),
+		}),
+		"secret":  resource.MakeSecret(resource.NewProperty("hunter2")),
+		"unknown": resource.MakeComputed(resource.NewProperty("")),
+		"null":    resource.NewNullProperty(),
+	}
+
+	want := `{` +
+		`"alpha":{"nested":{"a":1,"b":2}},` +
+		`"list":["z","a",{"x":true,"y":false}],` +
+		`"null":null,` +
+		`"secret":{"secret":"hunter2"},` +
+		`"unknown":"<computed>",` +
+		`"zeta":"last"` +
+		`}`
+	assert.Equal(t, want, SortedPropertyMapJSON(m))
+
+	// The rendering is valid JSON.
+	assert.True(t, json.Valid([]byte(SortedPropertyMapJSON(m))))
+}